@@ -0,0 +1,231 @@
+package logbase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// -------------------------------------------------------------
+// Async Sink
+// -------------------------------------------------------------
+
+// AsyncWriter queues lines for a background goroutine that writes them to
+// Writer, so slow outputs do not stall the logging call. Flush and Shutdown
+// wait for the queue with a deadline and report what was lost; Close
+// (logger.Close) does this for every sink.
+type AsyncWriter struct {
+	Writer     io.Writer
+	QueueSize  int  // lines; 1024 if zero
+	DropOnFull bool // drop and count lines while the queue is full instead of blocking
+
+	once     sync.Once
+	mu       sync.RWMutex // held shared to enqueue, exclusively to close the queue
+	queue    chan asyncItem
+	stop     chan struct{} // closed by Shutdown, so blocked enqueues give up
+	stopOnce sync.Once
+	done     chan struct{} // closed when the writer goroutine exits
+
+	closed    bool
+	abandoned atomic.Bool // stop writing: Shutdown's deadline passed
+	closer    io.Closer   // closed once the writer goroutine exits, for files from OpenSinks
+
+	queued  atomic.Uint64 // lines accepted
+	written atomic.Uint64 // lines written, successfully or not
+	dropped atomic.Uint64 // lines refused: queue full with DropOnFull, or after Shutdown
+
+	errMu sync.Mutex
+	err   error // first error from Writer
+}
+
+// asyncItem is a line, or a flush marker closed once everything queued
+// before it is written.
+type asyncItem struct {
+	line    *[]byte
+	flushed chan struct{}
+}
+
+var asyncLinePool = sync.Pool{New: func() any { return new([]byte) }}
+
+// maxPooledAsyncLine keeps unusually large lines from pinning memory in the pool.
+const maxPooledAsyncLine = 64 << 10
+
+func (w *AsyncWriter) start() {
+	size := w.QueueSize
+	if size <= 0 {
+		size = 1024
+	}
+	w.queue = make(chan asyncItem, size)
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for it := range w.queue {
+		if it.flushed != nil {
+			close(it.flushed)
+			continue
+		}
+		if !w.abandoned.Load() {
+			if _, err := w.Writer.Write(*it.line); err != nil {
+				w.errMu.Lock()
+				if w.err == nil {
+					w.err = err
+				}
+				w.errMu.Unlock()
+			}
+			w.written.Add(1)
+		}
+		if cap(*it.line) <= maxPooledAsyncLine {
+			asyncLinePool.Put(it.line)
+		}
+	}
+}
+
+// Write implements io.Writer. It copies p into the queue, blocking while
+// the queue is full unless DropOnFull is set or Shutdown is called.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.once.Do(w.start)
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.dropped.Add(1)
+		return 0, os.ErrClosed
+	}
+	line := asyncLinePool.Get().(*[]byte)
+	*line = append((*line)[:0], p...)
+	it := asyncItem{line: line}
+	w.queued.Add(1) // before the send, so written never runs ahead of it
+	if w.DropOnFull {
+		select {
+		case w.queue <- it:
+		default:
+			w.queued.Add(^uint64(0))
+			w.dropped.Add(1)
+			asyncLinePool.Put(line)
+		}
+	} else {
+		select {
+		case w.queue <- it:
+		case <-w.stop:
+			w.queued.Add(^uint64(0))
+			w.dropped.Add(1)
+			asyncLinePool.Put(line)
+			return 0, os.ErrClosed
+		}
+	}
+	return len(p), nil
+}
+
+// Flush waits until every line queued before the call is written, or ctx
+// is done.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	w.once.Do(w.start)
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case w.queue <- asyncItem{flushed: flushed}:
+		w.mu.RUnlock()
+	case <-w.stop:
+		w.mu.RUnlock()
+		return nil // Shutdown drains the queue and reports what it lost
+	case <-ctx.Done():
+		w.mu.RUnlock()
+		return fmt.Errorf("logger: async flush: %w", ctx.Err())
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("logger: async flush: %w", ctx.Err())
+	}
+}
+
+// Sync flushes the queue without a deadline, then syncs Writer if it can.
+func (w *AsyncWriter) Sync() error {
+	if err := w.Flush(context.Background()); err != nil {
+		return err
+	}
+	return syncWriter(w.Writer)
+}
+
+// Shutdown stops accepting lines and waits until the queue is written or
+// ctx is done. It reports the lines dropped, the lines still queued at the
+// deadline and the first write error. Writer is not closed; after the
+// deadline it may still be finishing the line in progress.
+func (w *AsyncWriter) Shutdown(ctx context.Context) error {
+	w.once.Do(w.start)
+	// Writers blocked on a full queue hold mu shared; stop releases them,
+	// so taking mu does not wait for the queue.
+	w.stopOnce.Do(func() { close(w.stop) })
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	var unwritten uint64
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		w.abandoned.Store(true)
+		unwritten = w.queued.Load() - w.written.Load()
+	}
+
+	var closeErr error
+	if w.closer != nil {
+		select {
+		case <-w.done:
+			closeErr = w.closer.Close()
+		default:
+			// the line in progress must not meet a closed file
+			go func() {
+				<-w.done
+				_ = w.closer.Close()
+			}()
+		}
+	}
+
+	w.errMu.Lock()
+	err := w.err
+	w.errMu.Unlock()
+	if err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if dropped := w.dropped.Load(); dropped > 0 || unwritten > 0 {
+		lost := fmt.Errorf("logger: async writer lost %d lines (%d dropped on a full queue, %d unwritten at shutdown)", dropped+unwritten, dropped, unwritten)
+		if unwritten > 0 {
+			lost = fmt.Errorf("%w: %w", lost, ctx.Err())
+		}
+		if err != nil {
+			return fmt.Errorf("%w; first write error: %w", lost, err)
+		}
+		return lost
+	}
+	if err != nil {
+		return fmt.Errorf("logger: async writer: %w", err)
+	}
+	return nil
+}
+
+// Close is Shutdown without a deadline.
+func (w *AsyncWriter) Close() error {
+	return w.Shutdown(context.Background())
+}
+
+// Dropped returns the number of lines dropped so far.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
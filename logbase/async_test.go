@@ -0,0 +1,140 @@
+package logbase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the AsyncWriter goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// blockingWriter blocks every Write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	syncBuffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.syncBuffer.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	var out syncBuffer
+	w := &AsyncWriter{Writer: &out, QueueSize: 16}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				fmt.Fprintf(w, "g%d %d\n", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "\n"); n != 1000 {
+		t.Errorf("flushed %d lines, want 1000", n)
+	}
+	if err := w.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) || w.Dropped() != 1 {
+		t.Errorf("write after Shutdown: %v, dropped %d", err, w.Dropped())
+	}
+}
+
+func TestAsyncWriterLosses(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	w := &AsyncWriter{Writer: out, QueueSize: 2, DropOnFull: true}
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	// the writer goroutine holds at most one line, the queue two more
+	if d := w.Dropped(); d < 7 || d > 8 {
+		t.Errorf("dropped %d lines, want 7 or 8", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := w.Shutdown(ctx)
+	close(out.release)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "async writer lost 10 lines") {
+		t.Errorf("Shutdown = %v", err)
+	}
+}
+
+func TestAsyncWriterShutdownBlocked(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	closed := make(chan struct{})
+	w := &AsyncWriter{Writer: out, QueueSize: 1, closer: closerFunc(func() error {
+		close(closed)
+		return nil
+	})}
+	// one line held by the writer goroutine, one queued, the rest blocked
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, err := fmt.Fprintf(w, "line %d\n", i)
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := w.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Shutdown took %v past its deadline", d)
+	}
+	refused := 0
+	for i := 0; i < 4; i++ {
+		if err := <-errs; errors.Is(err, os.ErrClosed) {
+			refused++
+		}
+	}
+	if refused != 2 {
+		t.Errorf("%d blocked writes refused, want 2", refused)
+	}
+
+	// the file stays open for the line in progress
+	select {
+	case <-closed:
+		t.Fatal("closed while a line was being written")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(out.release)
+	<-closed
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
@@ -0,0 +1,362 @@
+// Package logbase holds the parts of bench/logger that do not depend on the
+// logging backend, so the phuslu and zap loggers share them: the config
+// file format and its LOG_* environment overrides, the sink outputs they
+// open, RotatingFile and AsyncWriter.
+package logbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// -------------------------------------------------------------
+// Configuration File
+// -------------------------------------------------------------
+
+// Config is the declarative logger configuration, read from YAML or JSON.
+// Both bench/logger backends read the same format, so both benchmarks can
+// run from one file:
+//
+//	level: info
+//	components: "PFCP=debug,SESS=debug,*=info"
+//	rate_limits: "GIN=1000/s,NWDAF=200/s"
+//	format: console
+//	timestamp:
+//	  format: zap
+//	  utc: false
+//	color: auto
+//	theme: default
+//	caller: false
+//	panic_policy: repanic
+//	sinks:
+//	  - type: stdout
+//	  - type: file
+//	    path: /var/log/smf/debug.json
+//	    format: json
+//	    level: debug
+//	  - type: file
+//	    path: /var/log/smf/error.log
+//	    level: error
+//	    rotate:
+//	      max_size_mb: 100
+//	      interval: 24h
+//	      max_backups: 7
+//	      compress: true
+//	    async:
+//	      queue_size: 4096
+//	sampling:
+//	  tick: 1s
+//	  first: 100
+//	  thereafter: 100
+//	  key: UE_ID
+//	dedup:
+//	  max_hold: 10s
+//	redaction:
+//	  mode: hash
+//	  key: change-me
+type Config struct {
+	Level       string          `yaml:"level" json:"level"`             // global level, "info" if empty
+	Components  string          `yaml:"components" json:"components"`   // per-component levels, see logger.ParseLevelSpec
+	RateLimits  string          `yaml:"rate_limits" json:"rate_limits"` // per-component rate limits, see logger.ParseRateLimitSpec
+	Format      string          `yaml:"format" json:"format"`           // format of sinks without one: "console" (default), "json" or "logfmt"
+	Timestamp   TimestampConfig `yaml:"timestamp" json:"timestamp"`
+	Color       string          `yaml:"color" json:"color"`               // "auto" (default), "always" or "never"
+	Theme       string          `yaml:"theme" json:"theme"`               // "default" or "high-contrast"
+	Caller      bool            `yaml:"caller" json:"caller"`             // add the file:line column
+	PanicPolicy string          `yaml:"panic_policy" json:"panic_policy"` // after RecoverAndLog: "repanic" (default) or "exit"
+	Sinks       []SinkConfig    `yaml:"sinks" json:"sinks"`               // stdout if empty
+
+	Sampling  *SamplingConfig  `yaml:"sampling" json:"sampling"`   // off if absent, see logger.Sampling
+	Dedup     *DedupConfig     `yaml:"dedup" json:"dedup"`         // off if absent, see logger.WithDedup
+	Redaction *RedactionConfig `yaml:"redaction" json:"redaction"` // off if absent, see logger.Redaction
+}
+
+// RedactionConfig removes subscriber identities; see logger.Redaction.
+type RedactionConfig struct {
+	Mode     string   `yaml:"mode" json:"mode"`         // "mask" (default), "truncate" or "hash"
+	Fields   []string `yaml:"fields" json:"fields"`     // field names, the default list if absent
+	Patterns []string `yaml:"patterns" json:"patterns"` // regular expressions, the default list if absent
+	Keep     int      `yaml:"keep" json:"keep"`         // leading characters left in clear by mask and truncate
+	Key      string   `yaml:"key" json:"key"`           // secret for hash mode
+}
+
+// DedupConfig collapses identical consecutive entries; see logger.WithDedup.
+type DedupConfig struct {
+	MaxHold string `yaml:"max_hold" json:"max_hold"` // longest hold of repeats, "10s" if empty
+}
+
+// SamplingConfig thins out repeated entries; see logger.Sampling.
+type SamplingConfig struct {
+	Tick       string `yaml:"tick" json:"tick"`             // counting period, "1s" if empty
+	First      int    `yaml:"first" json:"first"`           // entries per level and message per tick
+	Thereafter int    `yaml:"thereafter" json:"thereafter"` // then every Thereafter-th; 0 drops the rest
+	Key        string `yaml:"key" json:"key"`               // optional field counted per value, e.g. UE_ID
+}
+
+// TimestampConfig selects the timestamp layout.
+type TimestampConfig struct {
+	// Format is "zap", "rfc3339", "epoch_ms", "epoch_ns", a time.Format
+	// layout, or "default" for each backend's historical layout.
+	Format string `yaml:"format" json:"format"`
+	UTC    bool   `yaml:"utc" json:"utc"`
+}
+
+// SinkConfig is one log destination.
+type SinkConfig struct {
+	Type   string `yaml:"type" json:"type"`     // "stdout", "stderr" or "file"
+	Path   string `yaml:"path" json:"path"`     // file sinks only
+	Format string `yaml:"format" json:"format"` // "console" (default), "json" or "logfmt"
+	Level  string `yaml:"level" json:"level"`   // minimum level of this sink, all if empty
+	Color  string `yaml:"color" json:"color"`   // console colors, the global color if empty
+
+	Rotate *RotateConfig `yaml:"rotate" json:"rotate"` // file sinks only, see RotatingFile
+	Async  *AsyncConfig  `yaml:"async" json:"async"`   // write through a queue, see AsyncWriter
+}
+
+// AsyncConfig queues a sink's lines for a background writer.
+type AsyncConfig struct {
+	QueueSize  int  `yaml:"queue_size" json:"queue_size"`     // lines, 1024 if zero
+	DropOnFull bool `yaml:"drop_on_full" json:"drop_on_full"` // drop lines instead of blocking when full
+}
+
+// RotateConfig is the rotation policy of a file sink. Zero fields are
+// disabled.
+type RotateConfig struct {
+	MaxSizeMB  int    `yaml:"max_size_mb" json:"max_size_mb"` // rotate at this size
+	Interval   string `yaml:"interval" json:"interval"`       // rotate every interval, e.g. "1h" or "24h"
+	MaxBackups int    `yaml:"max_backups" json:"max_backups"` // keep this many rotated files
+	MaxAgeDays int    `yaml:"max_age_days" json:"max_age_days"`
+	Compress   bool   `yaml:"compress" json:"compress"` // gzip rotated files
+}
+
+// Checker checks the settings whose values belong to the backend: level
+// names and specs, rate limits, formats, colors, themes, panic policies and
+// redaction rules. Each method returns an error for a value the backend
+// does not accept.
+type Checker interface {
+	Level(name string) error
+	LevelSpec(spec string) error
+	RateLimits(spec string) error
+	Format(name string) error
+	Color(mode string) error
+	Theme(name string) error
+	PanicPolicy(name string) error
+	Redaction(r *RedactionConfig) error
+}
+
+// Load reads and validates a configuration file. Files ending in ".json"
+// are read as JSON, anything else as YAML.
+func Load(path string, chk Checker) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %w", err)
+	}
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+	c, err := parse(data, format, chk)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse parses and validates a configuration in format "yaml" or "json".
+// Unknown keys are errors, so typos do not pass silently.
+func Parse(data []byte, format string, chk Checker) (*Config, error) {
+	c, err := parse(data, format, chk)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %w", err)
+	}
+	return c, nil
+}
+
+func parse(data []byte, format string, chk Checker) (*Config, error) {
+	var c Config
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return nil, err
+		}
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&c); err != nil && err != io.EOF {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+	if err := c.validate(chk); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &c, nil
+}
+
+// Validate reports every invalid setting in c.
+func (c *Config) Validate(chk Checker) error {
+	if err := c.validate(chk); err != nil {
+		return fmt.Errorf("logger: invalid config: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) validate(chk Checker) error {
+	var errs []error
+	if c.Level != "" {
+		if err := chk.Level(c.Level); err != nil {
+			errs = append(errs, fmt.Errorf("level: unknown level %q", c.Level))
+		}
+	}
+	if err := chk.LevelSpec(c.Components); err != nil {
+		errs = append(errs, fmt.Errorf("components: %s", strings.TrimPrefix(err.Error(), "logger: ")))
+	}
+	if err := chk.RateLimits(c.RateLimits); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limits: %s", strings.TrimPrefix(err.Error(), "logger: ")))
+	}
+	if err := chk.Format(c.Format); err != nil {
+		errs = append(errs, fmt.Errorf("format: unknown format %q", c.Format))
+	}
+	if err := chk.Color(c.Color); err != nil {
+		errs = append(errs, fmt.Errorf("color: %w", err))
+	}
+	if err := chk.PanicPolicy(c.PanicPolicy); err != nil {
+		errs = append(errs, fmt.Errorf("panic_policy: %w", err))
+	}
+	if err := chk.Theme(c.Theme); err != nil {
+		errs = append(errs, fmt.Errorf("theme: unknown theme %q", c.Theme))
+	}
+	for i, s := range c.Sinks {
+		switch s.Type {
+		case "stdout", "stderr":
+			if s.Path != "" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: path is only valid for file sinks", i))
+			}
+		case "file":
+			if s.Path == "" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: file sink needs a path", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sinks[%d]: unknown type %q", i, s.Type))
+		}
+		if s.Rotate != nil {
+			if s.Type != "file" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: rotate is only valid for file sinks", i))
+			}
+			if err := s.Rotate.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("sinks[%d]: rotate: %w", i, err))
+			}
+		}
+		if s.Async != nil && s.Async.QueueSize < 0 {
+			errs = append(errs, fmt.Errorf("sinks[%d]: async: queue_size: negative size %d", i, s.Async.QueueSize))
+		}
+		if err := chk.Format(s.Format); err != nil {
+			errs = append(errs, fmt.Errorf("sinks[%d]: unknown format %q", i, s.Format))
+		}
+		if s.Level != "" {
+			if err := chk.Level(s.Level); err != nil {
+				errs = append(errs, fmt.Errorf("sinks[%d]: unknown level %q", i, s.Level))
+			}
+		}
+		if s.Color != "" {
+			if err := chk.Color(s.Color); err != nil {
+				errs = append(errs, fmt.Errorf("sinks[%d]: %w", i, err))
+			}
+		}
+	}
+	if c.Sampling != nil {
+		if err := c.Sampling.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("sampling: %w", err))
+		}
+	}
+	if c.Dedup != nil {
+		if _, err := c.Dedup.Hold(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.Redaction != nil {
+		if err := chk.Redaction(c.Redaction); err != nil {
+			errs = append(errs, errors.New(strings.TrimPrefix(err.Error(), "logger: ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Hold returns the longest hold of repeats, zero for the default.
+func (d *DedupConfig) Hold() (time.Duration, error) {
+	if d.MaxHold == "" {
+		return 0, nil
+	}
+	hold, err := time.ParseDuration(d.MaxHold)
+	if err != nil || hold <= 0 {
+		return 0, fmt.Errorf("dedup: max_hold: invalid duration %q", d.MaxHold)
+	}
+	return hold, nil
+}
+
+func (s *SamplingConfig) validate() error {
+	var errs []error
+	if s.Tick != "" {
+		if d, err := time.ParseDuration(s.Tick); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("tick: invalid duration %q", s.Tick))
+		}
+	}
+	if s.First < 0 {
+		errs = append(errs, fmt.Errorf("first: negative count %d", s.First))
+	}
+	if s.Thereafter < 0 {
+		errs = append(errs, fmt.Errorf("thereafter: negative count %d", s.Thereafter))
+	}
+	return errors.Join(errs...)
+}
+
+// TickDuration returns the counting period, zero for the default.
+func (s *SamplingConfig) TickDuration() time.Duration {
+	tick, _ := time.ParseDuration(s.Tick)
+	return tick
+}
+
+func (r *RotateConfig) validate() error {
+	var errs []error
+	if r.MaxSizeMB < 0 {
+		errs = append(errs, fmt.Errorf("max_size_mb: negative size %d", r.MaxSizeMB))
+	}
+	if r.Interval != "" {
+		if d, err := time.ParseDuration(r.Interval); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("interval: invalid duration %q", r.Interval))
+		}
+	}
+	if r.MaxBackups < 0 {
+		errs = append(errs, fmt.Errorf("max_backups: negative count %d", r.MaxBackups))
+	}
+	if r.MaxAgeDays < 0 {
+		errs = append(errs, fmt.Errorf("max_age_days: negative age %d", r.MaxAgeDays))
+	}
+	return errors.Join(errs...)
+}
+
+// rotatingFile returns the writer of a file sink with this policy.
+func (r *RotateConfig) rotatingFile(path string, utc bool) *RotatingFile {
+	interval, _ := time.ParseDuration(r.Interval)
+	return &RotatingFile{
+		Filename:   path,
+		MaxSize:    int64(r.MaxSizeMB) << 20,
+		Interval:   interval,
+		MaxBackups: r.MaxBackups,
+		MaxAge:     time.Duration(r.MaxAgeDays) * 24 * time.Hour,
+		Compress:   r.Compress,
+		UTC:        utc,
+	}
+}
@@ -0,0 +1,95 @@
+package logbase
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// anyValue accepts every backend setting.
+type anyValue struct{}
+
+func (anyValue) Level(string) error               { return nil }
+func (anyValue) LevelSpec(string) error           { return nil }
+func (anyValue) RateLimits(string) error          { return nil }
+func (anyValue) Format(string) error              { return nil }
+func (anyValue) Color(string) error               { return nil }
+func (anyValue) Theme(string) error               { return nil }
+func (anyValue) PanicPolicy(string) error         { return nil }
+func (anyValue) Redaction(*RedactionConfig) error { return nil }
+
+// noValue rejects every backend setting.
+type noValue struct{}
+
+func (noValue) Level(string) error               { return errors.New("no") }
+func (noValue) LevelSpec(string) error           { return errors.New("logger: bad spec") }
+func (noValue) RateLimits(string) error          { return errors.New("logger: bad rate") }
+func (noValue) Format(string) error              { return errors.New("no") }
+func (noValue) Color(string) error               { return errors.New("bad color") }
+func (noValue) Theme(string) error               { return errors.New("no") }
+func (noValue) PanicPolicy(string) error         { return errors.New("bad policy") }
+func (noValue) Redaction(*RedactionConfig) error { return errors.New("logger: redaction: bad rule") }
+
+func TestParseChecker(t *testing.T) {
+	config := []byte("level: loud\nformat: xml\nsinks: [{type: stdout, level: low, color: pink}]\nredaction: {}")
+	if _, err := Parse(config, "yaml", anyValue{}); err != nil {
+		t.Fatalf("accepted values rejected: %v", err)
+	}
+	_, err := Parse(config, "yaml", noValue{})
+	if err == nil {
+		t.Fatal("rejected values accepted")
+	}
+	for _, want := range []string{`level: unknown level "loud"`, "components: bad spec", "rate_limits: bad rate", `format: unknown format "xml"`,
+		"color: bad color", "panic_policy: bad policy", `theme: unknown theme ""`, `sinks[0]: unknown level "low"`, "sinks[0]: bad color", "\nredaction: bad rule"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv(EnvOutput, "stderr, smf.log")
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvLevel, "debug")
+	c := &Config{Level: "info", Sinks: []SinkConfig{{Type: "stdout"}}}
+	if err := ApplyEnv(c, anyValue{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []SinkConfig{{Type: "stderr", Format: "json"}, {Type: "file", Path: "smf.log", Format: "json"}}
+	if c.Level != "debug" || c.Format != "json" || len(c.Sinks) != 2 || c.Sinks[0] != want[0] || c.Sinks[1] != want[1] {
+		t.Errorf("config = %+v", c)
+	}
+	if err := ApplyEnv(c, noValue{}); err == nil || !strings.Contains(err.Error(), EnvLevel+`="debug"`) {
+		t.Errorf("bad environment: %v", err)
+	}
+}
+
+func TestOpenSinks(t *testing.T) {
+	dir := t.TempDir()
+	configs := []SinkConfig{
+		{Type: "stdout"},
+		{Type: "file", Path: filepath.Join(dir, "smf.log"), Async: &AsyncConfig{QueueSize: 4}},
+		{Type: "file", Path: filepath.Join(dir, "missing", "smf.log")},
+	}
+	if _, _, err := OpenSinks(configs, false); err == nil {
+		t.Fatal("a file in a missing directory should fail")
+	}
+	writers, files, err := OpenSinks(configs[:2], false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(writers) != 2 || writers[0] != os.Stdout || len(files) != 1 {
+		t.Fatalf("writers %v, files %v", writers, files)
+	}
+	if _, err := writers[1].Write([]byte("queued\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloseSinks(t.Context(), files); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(configs[1].Path); err != nil || string(data) != "queued\n" {
+		t.Errorf("file = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,97 @@
+package logbase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// -------------------------------------------------------------
+// Environment Overrides
+// -------------------------------------------------------------
+
+// Environment variables that override the code defaults and the config file:
+//
+//	LOG_LEVEL=debug
+//	LOG_FORMAT=json                          (console, json or logfmt, for every configured output)
+//	LOG_OUTPUT=stdout,/var/log/smf/smf.log   (stdout, stderr or file paths)
+//	LOG_COLOR=never                          (auto, always or never)
+//	LOG_COMPONENT_LEVELS=PFCP=debug,*=info
+//	LOG_RATE_LIMITS=GIN=1000/s,NWDAF=200/s
+//
+// Empty variables are ignored.
+const (
+	EnvLevel           = "LOG_LEVEL"
+	EnvFormat          = "LOG_FORMAT"
+	EnvOutput          = "LOG_OUTPUT"
+	EnvColor           = "LOG_COLOR"
+	EnvComponentLevels = "LOG_COMPONENT_LEVELS"
+	EnvRateLimits      = "LOG_RATE_LIMITS"
+)
+
+// ApplyEnv applies the LOG_* variables to c. Invalid variables leave c as it
+// was; the error reports every one of them at once.
+func ApplyEnv(c *Config, chk Checker) error {
+	var errs []error
+	if v := os.Getenv(EnvLevel); v != "" {
+		if err := chk.Level(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: unknown level", EnvLevel, v))
+		} else {
+			c.Level = v
+		}
+	}
+	if v := os.Getenv(EnvColor); v != "" {
+		if err := chk.Color(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: want auto, always or never", EnvColor, v))
+		} else {
+			c.Color = v
+		}
+	}
+	if v := os.Getenv(EnvComponentLevels); v != "" {
+		if err := chk.LevelSpec(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %s", EnvComponentLevels, v, strings.TrimPrefix(err.Error(), "logger: ")))
+		} else {
+			c.Components = v
+		}
+	}
+	if v := os.Getenv(EnvRateLimits); v != "" {
+		if err := chk.RateLimits(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %s", EnvRateLimits, v, strings.TrimPrefix(err.Error(), "logger: ")))
+		} else {
+			c.RateLimits = v
+		}
+	}
+	if v := os.Getenv(EnvOutput); v != "" {
+		var sinks []SinkConfig
+		valid := true
+		for _, out := range strings.Split(v, ",") {
+			switch out = strings.TrimSpace(out); out {
+			case "":
+				valid = false
+				errs = append(errs, fmt.Errorf("%s=%q: empty output", EnvOutput, v))
+			case "stdout", "stderr":
+				sinks = append(sinks, SinkConfig{Type: out})
+			default:
+				sinks = append(sinks, SinkConfig{Type: "file", Path: out})
+			}
+		}
+		if valid {
+			c.Sinks = sinks
+		}
+	}
+	if v := os.Getenv(EnvFormat); v != "" {
+		if err := chk.Format(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: want console, json or logfmt", EnvFormat, v))
+		} else {
+			c.Format = v
+			for i := range c.Sinks {
+				c.Sinks[i].Format = v
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("logger: invalid environment: %w", errors.Join(errs...))
+	}
+	return nil
+}
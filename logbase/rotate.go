@@ -0,0 +1,392 @@
+package logbase
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -------------------------------------------------------------
+// Rotating File Sink
+// -------------------------------------------------------------
+
+// rotateTimeLayout names rotated files; it sorts chronologically.
+const rotateTimeLayout = "2006-01-02T15-04-05.000"
+
+// RotatingFile is an io.Writer for a log file that rotates by size and/or
+// time. Entries go to timestamped files next to Filename, e.g.
+// smf.2026-10-17T05-22-23.000.log, and Filename itself is kept as a symlink
+// to the current one. Each Write is one log line and is never split across
+// files. Rotated files are compressed and pruned in the background. A plain
+// file left at Filename by a run without rotation is kept as a rotated file.
+//
+// The zero value of every policy field disables it. A RotatingFile must not
+// be copied after first use.
+type RotatingFile struct {
+	Filename   string        // symlink to the current file, e.g. /var/log/smf/smf.log
+	MaxSize    int64         // rotate before a write would exceed MaxSize bytes
+	Interval   time.Duration // rotate at each multiple of Interval, e.g. 24 * time.Hour
+	MaxBackups int           // keep at most MaxBackups rotated files
+	MaxAge     time.Duration // remove rotated files older than MaxAge
+	Compress   bool          // gzip rotated files
+	UTC        bool          // use UTC in file names and for Interval boundaries
+
+	mu       sync.Mutex
+	file     *os.File
+	name     string    // path of file
+	size     int64     // bytes in file
+	deadline time.Time // next Interval rotation, zero without Interval
+	closed   bool
+
+	cleanup chan struct{} // wakes the background compress and prune loop
+	done    chan struct{} // closed when that loop exits
+
+	now func() time.Time // time.Now, replaced by tests
+}
+
+// Write implements io.Writer, rotating first if p would not fit in the
+// current file or its interval has passed.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.MaxSize ||
+		!r.deadline.IsZero() && !r.clock().Before(r.deadline) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Open opens the current file ahead of the first Write, so errors such as
+// a missing permission show at startup.
+func (r *RotatingFile) Open() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return os.ErrClosed
+	}
+	if r.file == nil {
+		return r.open()
+	}
+	return nil
+}
+
+// Rotate starts a new file now, e.g. on SIGHUP.
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return os.ErrClosed
+	}
+	if r.file == nil {
+		return r.open()
+	}
+	return r.rotate()
+}
+
+// Sync commits the current file to stable storage.
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Close closes the current file and waits for background compression.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	cleanup, done := r.cleanup, r.done
+	r.mu.Unlock()
+
+	if cleanup != nil {
+		close(cleanup)
+		<-done
+	}
+	return err
+}
+
+func (r *RotatingFile) clock() time.Time {
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	if r.UTC {
+		return now().UTC()
+	}
+	return now().Local()
+}
+
+// open continues the file Filename points to, or starts a new one.
+func (r *RotatingFile) open() error {
+	if r.Filename == "" {
+		return errors.New("logger: RotatingFile needs a Filename")
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Filename), 0o755); err != nil {
+		return fmt.Errorf("logger: %w", err)
+	}
+	if target, err := os.Readlink(r.Filename); err == nil {
+		name := filepath.Join(filepath.Dir(r.Filename), filepath.Base(target))
+		if started, ok := r.parseName(filepath.Base(name)); ok {
+			f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+			if err == nil {
+				if fi, err := f.Stat(); err == nil {
+					r.setFile(f, name, fi.Size(), started)
+					return nil
+				}
+				f.Close()
+			}
+		}
+	}
+	return r.create()
+}
+
+// rotate closes the current file and starts a new one.
+func (r *RotatingFile) rotate() error {
+	if err := r.create(); err != nil {
+		return err
+	}
+	select {
+	case r.cleanup <- struct{}{}:
+	default: // a pass is already pending
+	}
+	return nil
+}
+
+// create starts a timestamped file and points Filename at it.
+func (r *RotatingFile) create() error {
+	if err := r.adoptPlainFile(); err != nil {
+		return err
+	}
+	now := r.clock()
+	name := r.newName(now)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: %w", err)
+	}
+
+	// replace the symlink atomically so readers never see it missing
+	tmp := r.Filename + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(filepath.Base(name), tmp); err == nil {
+		if err := os.Rename(tmp, r.Filename); err != nil {
+			_ = os.Remove(tmp)
+		}
+	}
+
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+	r.setFile(f, name, 0, now)
+	return nil
+}
+
+// newName returns an unused name for a file started at t.
+func (r *RotatingFile) newName(t time.Time) string {
+	dir := filepath.Dir(r.Filename)
+	base, ext := r.nameParts()
+	name := filepath.Join(dir, base+"."+t.Format(rotateTimeLayout)+ext)
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		// rotating twice within a millisecond
+		name = filepath.Join(dir, fmt.Sprintf("%s.%s-%d%s", base, t.Format(rotateTimeLayout), i, ext))
+	}
+	return name
+}
+
+// adoptPlainFile makes way for the Filename symlink. A regular file there,
+// written by a run without rotation, is renamed to a rotated file stamped
+// with its modification time, so it is compressed and pruned like the
+// others instead of being replaced. Anything but a file or a symlink is an
+// error.
+func (r *RotatingFile) adoptPlainFile() error {
+	fi, err := os.Lstat(r.Filename)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("logger: %w", err)
+	case fi.Mode()&fs.ModeSymlink != 0:
+		return nil
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf("logger: %s is neither a file nor a symlink", r.Filename)
+	}
+	modified := fi.ModTime().Local()
+	if r.UTC {
+		modified = modified.UTC()
+	}
+	if err := os.Rename(r.Filename, r.newName(modified)); err != nil {
+		return fmt.Errorf("logger: %w", err)
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (r *RotatingFile) setFile(f *os.File, name string, size int64, started time.Time) {
+	r.file, r.name, r.size = f, name, size
+	r.deadline = time.Time{}
+	if r.Interval > 0 {
+		// align to the interval in the file's time zone, so 24h rotates at midnight
+		_, offset := started.Zone()
+		shift := time.Duration(offset) * time.Second
+		r.deadline = started.Add(shift).Truncate(r.Interval).Add(r.Interval - shift)
+	}
+	if r.cleanup == nil {
+		r.cleanup = make(chan struct{}, 1)
+		r.done = make(chan struct{})
+		go r.cleanupLoop(r.cleanup, r.done)
+		r.cleanup <- struct{}{} // prune what earlier runs left
+	}
+}
+
+// nameParts splits Filename into the base and extension of rotated files.
+func (r *RotatingFile) nameParts() (base, ext string) {
+	base = filepath.Base(r.Filename)
+	ext = filepath.Ext(base)
+	return strings.TrimSuffix(base, ext), ext
+}
+
+// parseName reports whether name is one of r's files, returning its start.
+func (r *RotatingFile) parseName(name string) (time.Time, bool) {
+	base, ext := r.nameParts()
+	name = strings.TrimSuffix(name, ".gz")
+	if !strings.HasPrefix(name, base+".") || !strings.HasSuffix(name, ext) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ext)
+	if len(stamp) < len(rotateTimeLayout) {
+		return time.Time{}, false
+	}
+	loc := time.Local
+	if r.UTC {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(rotateTimeLayout, stamp[:len(rotateTimeLayout)], loc)
+	return t, err == nil
+}
+
+// cleanupLoop compresses and prunes rotated files until cleanup is closed.
+func (r *RotatingFile) cleanupLoop(cleanup <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	for range cleanup {
+		r.mu.Lock()
+		now := r.clock()
+		r.mu.Unlock()
+		r.cleanupOnce(now)
+	}
+}
+
+// cleanupOnce compresses and prunes every rotated file except the current
+// one. A rotation may start a new file during the pass, so each file is
+// checked against the current one just before it is touched.
+func (r *RotatingFile) cleanupOnce(now time.Time) {
+	dir := filepath.Dir(r.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type rotated struct {
+		path    string
+		started time.Time
+	}
+	var files []rotated
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		started, ok := r.parseName(e.Name())
+		if !ok || !e.Type().IsRegular() || r.isCurrent(path) {
+			continue
+		}
+		if r.Compress && !strings.HasSuffix(path, ".gz") {
+			if _, err := os.Stat(path + ".gz"); err == nil {
+				_ = os.Remove(path) // compressed before a crash
+				continue
+			}
+			if gz, err := compressFile(path); err == nil {
+				path = gz
+			}
+		}
+		files = append(files, rotated{path, started})
+	}
+
+	// newest first; a file ended when the next one started
+	sort.Slice(files, func(i, j int) bool { return files[i].started.After(files[j].started) })
+	cutoff := now.Add(-r.MaxAge)
+	for i, f := range files {
+		expired := r.MaxAge > 0 && i > 0 && files[i-1].started.Before(cutoff)
+		if r.MaxBackups > 0 && i >= r.MaxBackups || expired {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+// isCurrent reports whether path is the file being written. Once it is
+// not, it never is again, since rotations always start new files.
+func (r *RotatingFile) isCurrent(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return path == r.name
+}
+
+// compressFile gzips path into path.gz and removes path. A failed attempt
+// leaves path alone.
+func compressFile(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	tmp := path + ".gz.tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return path + ".gz", os.Remove(path)
+}
@@ -0,0 +1,285 @@
+package logbase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// readRotated returns the lines of every file in dir, decompressing
+// rotated ones.
+func readRotated(t *testing.T, dir string) (lines []string, files []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		files = append(files, e.Name())
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r = zr
+		}
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		f.Close()
+	}
+	return lines, files
+}
+
+// fakeClock is a settable time source, safe for the cleanup goroutine.
+type fakeClock struct{ ns atomic.Int64 }
+
+func newFakeClock(t time.Time) *fakeClock {
+	c := &fakeClock{}
+	c.ns.Store(t.UnixNano())
+	return c
+}
+
+func (c *fakeClock) now() time.Time      { return time.Unix(0, c.ns.Load()) }
+func (c *fakeClock) add(d time.Duration) { c.ns.Add(int64(d)) }
+
+func TestRotatingFileSize(t *testing.T) {
+	dir := t.TempDir()
+	r := &RotatingFile{Filename: filepath.Join(dir, "smf.log"), MaxSize: 4 << 10, Compress: true}
+
+	// concurrent writers: every line must land whole in exactly one file
+	const writers, perWriter = 8, 500
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				fmt.Fprintf(r, "writer=%d seq=%04d Packet processed successfully\n", w, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, files := readRotated(t, dir)
+	if len(lines) != writers*perWriter {
+		t.Errorf("got %d lines, want %d", len(lines), writers*perWriter)
+	}
+	seen := make(map[string]bool)
+	for _, l := range lines {
+		if !strings.HasSuffix(l, " Packet processed successfully") || seen[l] {
+			t.Fatalf("split or duplicated line %q", l)
+		}
+		seen[l] = true
+	}
+
+	var plain, gz int
+	for _, name := range files {
+		if strings.HasSuffix(name, ".gz") {
+			gz++
+		} else {
+			plain++
+		}
+	}
+	if plain != 1 || gz < 20 {
+		t.Errorf("got %d plain and %d compressed files, want 1 and about 30: %v", plain, gz, files)
+	}
+
+	target, err := os.Readlink(filepath.Join(dir, "smf.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, target)); err != nil || fi.Size() > 4<<10 {
+		t.Errorf("symlink target %s: %v, %v", target, fi, err)
+	}
+}
+
+func TestRotatingFileRotateWhileCleaning(t *testing.T) {
+	dir := t.TempDir()
+	r := &RotatingFile{Filename: filepath.Join(dir, "smf.log"), Compress: true}
+
+	// rotations racing the cleanup passes they start must not compress or
+	// remove the file being written
+	const writers, perWriter = 4, 2000
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				fmt.Fprintf(r, "writer=%d seq=%04d Packet processed successfully\n", w, i)
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		if err := r.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, _ := readRotated(t, dir)
+	if len(lines) != writers*perWriter {
+		t.Errorf("got %d lines, want %d", len(lines), writers*perWriter)
+	}
+}
+
+func TestRotatingFilePlainFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "smf.log")
+	if err := os.WriteFile(path, []byte("old run\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &RotatingFile{Filename: path, UTC: true}
+	if _, err := fmt.Fprintln(r, "new run"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines, files := readRotated(t, dir)
+	if strings.Join(lines, ",") != "old run,new run" || files[0] != "smf.2026-10-16T08-00-00.000.log" {
+		t.Errorf("got lines %q in %v", lines, files)
+	}
+	if _, err := os.Readlink(path); err != nil {
+		t.Errorf("%s is not a symlink: %v", path, err)
+	}
+
+	// anything else at Filename is left alone
+	busy := filepath.Join(dir, "busy.log")
+	if err := os.Mkdir(busy, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&RotatingFile{Filename: busy}).Open(); err == nil {
+		t.Errorf("a directory at Filename should fail")
+	}
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock(time.Date(2026, 10, 17, 23, 59, 0, 0, time.UTC))
+	r := &RotatingFile{
+		Filename:   filepath.Join(dir, "smf.log"),
+		Interval:   24 * time.Hour,
+		MaxBackups: 2,
+		UTC:        true,
+		now:        clock.now,
+	}
+
+	for day := 0; day < 5; day++ {
+		if day > 0 {
+			clock.add(24 * time.Hour)
+		}
+		fmt.Fprintf(r, "day %d\n", day)
+	}
+	clock.add(30 * time.Second) // 23:59:30, still before midnight
+	fmt.Fprintln(r, "same day")
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, files := readRotated(t, dir)
+	want := []string{"smf.2026-10-19T23-59-00.000.log", "smf.2026-10-20T23-59-00.000.log", "smf.2026-10-21T23-59-00.000.log"}
+	if strings.Join(files, " ") != strings.Join(want, " ") {
+		t.Errorf("files = %v, want %v (2 backups and the current file)", files, want)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "smf.log"))
+	if string(data) != "day 4\nsame day\n" {
+		t.Errorf("current file = %q", data)
+	}
+
+	// a restart continues the current file until its interval ends
+	r = &RotatingFile{Filename: filepath.Join(dir, "smf.log"), Interval: 24 * time.Hour, UTC: true, now: clock.now}
+	fmt.Fprintln(r, "restarted")
+	r.Close()
+	data, _ = os.ReadFile(filepath.Join(dir, "smf.log"))
+	if string(data) != "day 4\nsame day\nrestarted\n" {
+		t.Errorf("after restart, current file = %q", data)
+	}
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	r := &RotatingFile{Filename: filepath.Join(dir, "smf.log"), MaxAge: 3 * 24 * time.Hour, UTC: true, now: clock.now}
+	for day := 0; day < 10; day++ {
+		fmt.Fprintf(r, "day %d\n", day)
+		if err := r.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+		clock.add(24 * time.Hour)
+	}
+	r.Close()
+
+	// files are removed once they stopped being written more than 3 days ago
+	lines, _ := readRotated(t, dir)
+	if strings.Join(lines, ",") != "day 7,day 8,day 9" {
+		t.Errorf("kept %v", lines)
+	}
+}
+
+func TestConfigRotate(t *testing.T) {
+	c, err := Parse([]byte(`
+sinks:
+  - type: file
+    path: smf.log
+    rotate: {max_size_mb: 100, interval: 24h, max_backups: 7, max_age_days: 30, compress: true}
+`), "yaml", anyValue{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := c.Sinks[0].Rotate.rotatingFile("smf.log", false)
+	if f.MaxSize != 100<<20 || f.Interval != 24*time.Hour || f.MaxBackups != 7 || f.MaxAge != 30*24*time.Hour || !f.Compress {
+		t.Errorf("rotating file = %+v", f)
+	}
+
+	_, err = Parse([]byte(`
+sinks:
+  - type: stdout
+    rotate: {interval: daily, max_backups: -1}
+`), "yaml", anyValue{})
+	for _, want := range []string{"sinks[0]: rotate is only valid for file sinks", `interval: invalid duration "daily"`, "max_backups: negative count -1"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v does not contain %q", err, want)
+		}
+	}
+}
+
+func BenchmarkRotatingFile(b *testing.B) {
+	r := &RotatingFile{Filename: filepath.Join(b.TempDir(), "smf.log"), MaxSize: 1 << 20}
+	defer r.Close()
+	line := []byte("2026-10-17 | 05:22:23.348 | INFO  | PFCP  | Packet processed successfully seid=42\n")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Write(line)
+	}
+}
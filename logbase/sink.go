@@ -0,0 +1,95 @@
+package logbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// -------------------------------------------------------------
+// Sink Outputs
+// -------------------------------------------------------------
+
+// OpenSinks opens the outputs of configs: writers[i] is the output of
+// configs[i], and files are the ones to close when the logger stops using
+// them. On error nothing is left open. Rotated file names use UTC if utc is
+// set.
+func OpenSinks(configs []SinkConfig, utc bool) (writers []io.Writer, files []io.Closer, err error) {
+	for _, c := range configs {
+		var w io.Writer
+		var file io.Closer
+		switch c.Type {
+		case "stdout":
+			w = os.Stdout
+		case "stderr":
+			w = os.Stderr
+		case "file":
+			if c.Rotate != nil {
+				f := c.Rotate.rotatingFile(c.Path, utc)
+				if err := f.Open(); err != nil {
+					_ = CloseSinks(context.Background(), files)
+					return nil, nil, err
+				}
+				w, file = f, f
+				break
+			}
+			f, err := os.OpenFile(c.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+			if err != nil {
+				_ = CloseSinks(context.Background(), files)
+				return nil, nil, fmt.Errorf("logger: %w", err)
+			}
+			w, file = f, f
+		}
+		if c.Async != nil {
+			a := &AsyncWriter{Writer: w, QueueSize: c.Async.QueueSize, DropOnFull: c.Async.DropOnFull, closer: file}
+			w, file = a, a // closes the file once its queue is written
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+		writers = append(writers, w)
+	}
+	return writers, files, nil
+}
+
+// FlushWriter flushes w: AsyncWriter queues until ctx is done, then files.
+func FlushWriter(ctx context.Context, w io.Writer) error {
+	if a, ok := w.(*AsyncWriter); ok {
+		if err := a.Flush(ctx); err != nil {
+			return err
+		}
+		w = a.Writer
+	}
+	if err := syncWriter(w); err != nil {
+		return fmt.Errorf("logger: sync: %w", err)
+	}
+	return nil
+}
+
+// syncWriter commits w to stable storage if it can. Terminals and pipes
+// cannot sync, so os.Stdout and os.Stderr are skipped.
+func syncWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// CloseSinks closes the sinks in order, giving AsyncWriter queues until
+// ctx is done.
+func CloseSinks(ctx context.Context, sinks []io.Closer) error {
+	var errs []error
+	for _, c := range sinks {
+		if s, ok := c.(interface{ Shutdown(context.Context) error }); ok {
+			errs = append(errs, s.Shutdown(ctx))
+		} else {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
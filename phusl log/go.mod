@@ -2,4 +2,11 @@ module bench
 
 go 1.24.0
 
-require github.com/phuslu/log v1.0.115
+require (
+	bench/logbase v0.0.0
+	github.com/phuslu/log v1.0.115
+)
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+replace bench/logbase => ../logbase
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.DebugLevel)
+
+	w := &LineWriter{Logger: NewComponentLogger("GIN"), DetectLevel: true}
+	fmt.Fprint(w, "[GIN-debug] Listening and serving HTTP on :8080\n[GIN] 200 | GET /health\n\n")
+	fmt.Fprint(w, "[GIN-debug] [WARNING] Running in \"debug\" mode")
+	fmt.Fprint(w, "\r\nERROR: upstream gone\nhttp: TLS handshake error\n[FATAL] not fatal here\npartial")
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []struct{ level, suffix string }{
+		{"DEBUG", "| GIN   | Listening and serving HTTP on :8080"},
+		{"INFO ", "| GIN   | [GIN] 200 | GET /health"},
+		{"WARN ", `| GIN   | Running in "debug" mode`},
+		{"ERR  ", "| GIN   | upstream gone"},
+		{"INFO ", "| GIN   | http: TLS handshake error"},
+		{"ERR  ", "| GIN   | not fatal here"},
+		{"INFO ", "| GIN   | partial"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w.level) || !strings.HasSuffix(lines[i], w.suffix) {
+			t.Errorf("line %d = %q, want level %q and suffix %q", i, lines[i], w.level, w.suffix)
+		}
+	}
+}
+
+func TestLineWriterLongLine(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	// output without newlines is logged in bounded pieces
+	w := &LineWriter{Logger: NewComponentLogger("GIN")}
+	chunk := strings.Repeat("x", 1000)
+	for i := 0; i < 150; i++ {
+		fmt.Fprint(w, chunk)
+	}
+	if len(w.partial) >= maxCapturedLine || cap(w.partial) > 2*maxCapturedLine {
+		t.Errorf("holding %d bytes, capacity %d", len(w.partial), cap(w.partial))
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	got := 0
+	for _, l := range lines {
+		got += strings.Count(l, "x")
+	}
+	if got != 150*len(chunk) {
+		t.Errorf("logged %d bytes, want %d", got, 150*len(chunk))
+	}
+}
+
+func TestStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	NewStdLogger(NewComponentLogger("SBI"), log.ErrorLevel).Printf("http: TLS handshake error from %s: EOF", "10.0.0.1:4711")
+	restore := RedirectStdLog(NewComponentLogger("MAIN"), log.InfoLevel)
+	stdlog.Print("started")
+	stdlog.Print("[DEBUG] filtered out")
+	restore()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 ||
+		!strings.HasSuffix(lines[0], "| SBI   | http: TLS handshake error from 10.0.0.1:4711: EOF") || !strings.Contains(lines[0], "ERR  ") ||
+		!strings.HasSuffix(lines[1], "| MAIN  | started") {
+		t.Errorf("std log output:\n%s", buf.String())
+	}
+	if stdlog.Flags() != stdlog.LstdFlags {
+		t.Errorf("restore left flags %d", stdlog.Flags())
+	}
+}
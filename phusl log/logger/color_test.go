@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+	for _, tc := range []struct {
+		mode     ColorMode
+		noColor  string
+		force    string
+		w        io.Writer
+		expected bool
+	}{
+		{ColorAuto, "", "", &buf, false},
+		{ColorAlways, "", "", &buf, true},
+		{ColorNever, "", "1", os.Stdout, false},
+		{ColorAuto, "", "1", &buf, true},
+		{ColorAuto, "", "0", &buf, false},
+		{ColorAuto, "1", "1", &buf, false},
+		{ColorAlways, "1", "", &buf, true},
+	} {
+		t.Setenv("NO_COLOR", tc.noColor)
+		t.Setenv("FORCE_COLOR", tc.force)
+		if got := useColor(tc.mode, tc.w); got != tc.expected {
+			t.Errorf("useColor(%v) NO_COLOR=%q FORCE_COLOR=%q = %v, want %v", tc.mode, tc.noColor, tc.force, got, tc.expected)
+		}
+	}
+}
+
+func TestStripANSIWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := stripANSIWriter{&buf}
+	in := "\033[0m\033[32mINFO \033[0m | \033[41m\033[37mGIN\033[0m \033[1;38;5;208mcolored\033[m\033(B\n"
+	if n, err := w.Write([]byte(in)); n != len(in) || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if got, want := buf.String(), "INFO  | GIN colored\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestConsoleFormatterPlain(t *testing.T) {
+	var buf bytes.Buffer
+	f := &consoleFormatter{}
+	l := log.Logger{Level: log.InfoLevel, Writer: &log.ConsoleWriter{Formatter: f.Format, Writer: &buf}}
+	l.Error().Str("component", "PFCP").Msg("plain")
+	if got := buf.String(); bytes.IndexByte(buf.Bytes(), 0x1b) >= 0 || !bytes.HasSuffix(buf.Bytes(), []byte(" | ERR   | PFCP  | plain\n")) {
+		t.Errorf("plain line = %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Component Sub-Loggers
+// -------------------------------------------------------------
+
+// ComponentLogger wraps the global logger and tags every entry with a
+// "component" field, which customConsoleFormatter prints as its own column.
+type ComponentLogger struct {
+	component string
+	context   log.Context // pre-encoded `"component":"<name>"`, appended to each entry
+	caller    int         // Entry.Caller depth of this logger alone, see CallerLogger; 0 uses callerDepth
+
+	*componentState // shared with the loggers returned by CallerLogger
+}
+
+// componentState is the level and rate limit of one component.
+type componentState struct {
+	// minSeverity is this component's level as a severity, from the global
+	// level or a per-component override; see SetComponentLevels.
+	minSeverity atomic.Int64
+
+	limit atomic.Pointer[rateBucket] // nil unless rate limited; see SetRateLimits
+}
+
+// Component loggers, mirroring the zap bench/logger set.
+var (
+	MainLog     *ComponentLogger
+	NfLog       *ComponentLogger
+	InitLog     *ComponentLogger
+	CfgLog      *ComponentLogger
+	CtxLog      *ComponentLogger
+	GinLog      *ComponentLogger
+	SBILog      *ComponentLogger
+	ConsumerLog *ComponentLogger
+	GsmLog      *ComponentLogger
+	PfcpLog     *ComponentLogger
+	PduSessLog  *ComponentLogger
+	ChargingLog *ComponentLogger
+	UtilLog     *ComponentLogger
+	NwdafLog    *ComponentLogger
+)
+
+// NewComponentLogger returns the ComponentLogger of component name, which
+// automatically attaches "component" to each log entry. It is created on
+// first use; later calls return the same logger.
+func NewComponentLogger(name string) *ComponentLogger {
+	return componentLogger(name)
+}
+
+// CallerLogger returns c with the caller column of WithCallerSkip(skip), for
+// one logger while the caller options given to Initialize are off. It shares
+// the level and rate limit of c.
+func CallerLogger(c *ComponentLogger, skip int) *ComponentLogger {
+	l := *c
+	l.caller = 1 + skip
+	return &l
+}
+
+// initComponentLoggers creates the package-level component loggers.
+func initComponentLoggers() {
+	MainLog = NewComponentLogger("MAIN")
+	NfLog = NewComponentLogger("NF")
+	InitLog = NewComponentLogger("INIT")
+	CfgLog = NewComponentLogger("CFG")
+	CtxLog = NewComponentLogger("CTX")
+	GinLog = NewComponentLogger("GIN")
+	SBILog = NewComponentLogger("SBI")
+	ConsumerLog = NewComponentLogger("CONS")
+	GsmLog = NewComponentLogger("GSM")
+	PfcpLog = NewComponentLogger("PFCP")
+	PduSessLog = NewComponentLogger("SESS")
+	ChargingLog = NewComponentLogger("CHARGE")
+	UtilLog = NewComponentLogger("UTIL")
+	NwdafLog = NewComponentLogger("NWDAF")
+}
+
+// Component returns the component name, e.g. "PFCP".
+func (c *ComponentLogger) Component() string {
+	return c.component
+}
+
+// Trace starts a new message with trace level.
+func (c *ComponentLogger) Trace() *log.Entry {
+	return c.entry(log.TraceLevel, nil)
+}
+
+// Debug starts a new message with debug level.
+func (c *ComponentLogger) Debug() *log.Entry {
+	return c.entry(log.DebugLevel, nil)
+}
+
+// Info starts a new message with info level.
+func (c *ComponentLogger) Info() *log.Entry {
+	return c.entry(log.InfoLevel, nil)
+}
+
+// Warn starts a new message with warning level.
+func (c *ComponentLogger) Warn() *log.Entry {
+	return c.entry(log.WarnLevel, nil)
+}
+
+// Notice starts a new message with notice level.
+func (c *ComponentLogger) Notice() *log.Entry {
+	return c.entry(NoticeLevel, nil)
+}
+
+// Error starts a new message with error level.
+func (c *ComponentLogger) Error() *log.Entry {
+	return c.entry(log.ErrorLevel, nil)
+}
+
+// Fatal starts a new message with fatal level.
+func (c *ComponentLogger) Fatal() *log.Entry {
+	return c.entry(log.FatalLevel, nil)
+}
+
+// Panic starts a new message with panic level.
+func (c *ComponentLogger) Panic() *log.Entry {
+	return c.entry(log.PanicLevel, nil)
+}
+
+// WithLevel starts a new message with a built-in or registered level.
+func (c *ComponentLogger) WithLevel(level log.Level) *log.Entry {
+	return c.entry(level, nil)
+}
+
+// start begins an entry at a built-in or registered level, or returns nil
+// if the level is filtered out. Registered levels go through Logger.Log,
+// which writes no level of its own.
+func (c *ComponentLogger) start(level log.Level) *log.Entry {
+	if c.disabled(level) {
+		return nil
+	}
+	return levelEntry(&globalLogger, level)
+}
+
+// levelEntry starts an entry of l at level, which may be a registered level
+// that phuslu/log cannot name itself.
+func levelEntry(l *log.Logger, level log.Level) *log.Entry {
+	if level < firstCustomLevel {
+		return l.WithLevel(level)
+	}
+	e := l.Log().Str("level", levelName(level))
+	e.Level = level // for sink levels; Msg only acts on FatalLevel and PanicLevel
+	return e
+}
+
+// disabled reports whether level is below this component's level or the
+// component's rate limit is used up. It runs before globalLogger starts an
+// entry, so filtered calls cost an atomic load or two.
+func (c *ComponentLogger) disabled(level log.Level) bool {
+	severity := levelSeverity(level)
+	return int64(severity) < c.minSeverity.Load() || c.limited(severity)
+}
+
+// entry starts an entry at level tagged with the component, fields and, if
+// enabled, the caller. It must be called directly from the level methods of
+// ComponentLogger and ContextLogger so the frame count is fixed:
+// Entry.Caller -> entry -> ComponentLogger.Info -> call site.
+func (c *ComponentLogger) entry(level log.Level, fields log.Context) *log.Entry {
+	if c.disabled(level) {
+		return nil
+	}
+	e := levelEntry(&globalLogger, level).Context(c.context).Context(fields)
+	depth := c.caller
+	if depth == 0 {
+		depth = callerDepth
+	}
+	if depth != 0 {
+		e = e.Caller(depth + 2)
+	}
+	return e
+}
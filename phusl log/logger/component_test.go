@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+// captureLogger returns a logger that formats with customConsoleFormatter into buf.
+func captureLogger(buf *bytes.Buffer, level log.Level) log.Logger {
+	return log.Logger{
+		Level: level,
+		Writer: &log.ConsoleWriter{
+			Formatter: customConsoleFormatter,
+			Writer:    buf,
+		},
+	}
+}
+
+// useLevel points the component loggers at a capturing logger filtering at level.
+func useLevel(t testing.TB, buf *bytes.Buffer, level log.Level) {
+	saved, savedLevel := globalLogger, GetLevel()
+	t.Cleanup(func() { globalLogger = saved; SetLevel(savedLevel) })
+	globalLogger = captureLogger(buf, builtinThreshold(level))
+	SetLevel(level)
+}
+
+func TestComponentLoggerColumn(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	pfcp := NewComponentLogger("PFCP")
+	pfcp.Info().Msg("Association setup")
+	pfcp.Debug().Msg("filtered out")
+	globalLogger.Info().Msg("no component")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "\033[32mINFO \033[0m | PFCP  | Association setup") {
+		t.Errorf("component line = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "\033[32mINFO \033[0m | no component") {
+		t.Errorf("plain line = %q", lines[1])
+	}
+}
+
+func TestComponentLoggerCaller(t *testing.T) {
+	var buf bytes.Buffer
+	savedDepth := callerDepth
+	defer func() { callerDepth = savedDepth }()
+	useLevel(t, &buf, log.InfoLevel)
+	callerDepth = 1
+
+	NewComponentLogger("PFCP").Info().Msg("with caller")
+
+	if got := buf.String(); !strings.Contains(got, " | PFCP  | logger/component_test.go:") {
+		t.Errorf("caller column missing or wrong: %q", got)
+	}
+}
+
+func TestCallerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	savedDepth := callerDepth
+	defer func() { callerDepth = savedDepth }()
+	useLevel(t, &buf, log.InfoLevel)
+	t.Cleanup(func() { _ = SetComponentLevels("") })
+	callerDepth = 0
+
+	pfcp := NewComponentLogger("PFCP")
+	c := CallerLogger(pfcp, 0)
+	c.Info().Msg("with caller")
+	pfcp.Info().Msg("without caller")
+	// the level is shared with pfcp
+	if err := SetComponentLevels("PFCP=error"); err != nil {
+		t.Fatal(err)
+	}
+	c.Warn().Msg("filtered")
+
+	got := buf.String()
+	if !strings.Contains(got, " | PFCP  | logger/component_test.go:") || strings.Count(got, "component_test.go") != 1 {
+		t.Errorf("caller column missing or wrong: %q", got)
+	}
+	if strings.Contains(got, "filtered") {
+		t.Errorf("CallerLogger should follow the component level: %q", got)
+	}
+}
+
+func benchmarkComponentLogger(b *testing.B, depth int) {
+	savedDepth := callerDepth
+	defer func() { callerDepth = savedDepth }()
+	useLevel(b, nil, log.InfoLevel)
+	globalLogger.Writer = &log.ConsoleWriter{Formatter: customConsoleFormatter, Writer: io.Discard}
+	callerDepth = depth
+
+	c := NewComponentLogger("MAIN")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Info().Int("UE_ID", 1001).Msg("Packet processed successfully")
+	}
+}
+
+func BenchmarkComponentLogger(b *testing.B)       { benchmarkComponentLogger(b, 0) }
+func BenchmarkComponentLoggerCaller(b *testing.B) { benchmarkComponentLogger(b, 1) }
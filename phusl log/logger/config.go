@@ -0,0 +1,269 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"bench/logbase"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Configuration File (shared with the zap bench/logger)
+// -------------------------------------------------------------
+
+// The configuration format, its environment overrides and the file sinks
+// are shared with the zap bench/logger through bench/logbase, so both
+// benchmarks can run from one file; see logbase.Config for the format.
+type (
+	Config          = logbase.Config
+	SinkConfig      = logbase.SinkConfig
+	RotateConfig    = logbase.RotateConfig
+	AsyncConfig     = logbase.AsyncConfig
+	TimestampConfig = logbase.TimestampConfig
+	SamplingConfig  = logbase.SamplingConfig
+	DedupConfig     = logbase.DedupConfig
+	RedactionConfig = logbase.RedactionConfig
+
+	// RotatingFile is a file sink that rotates by size and/or time; see
+	// logbase.RotatingFile.
+	RotatingFile = logbase.RotatingFile
+	// AsyncWriter queues a sink's lines for a background writer; see
+	// logbase.AsyncWriter.
+	AsyncWriter = logbase.AsyncWriter
+)
+
+// LoadConfig reads and validates a configuration file, then applies the
+// LOG_* environment overrides on top (see ConfigFromEnv). Files ending in
+// ".json" are read as JSON, anything else as YAML.
+func LoadConfig(path string) (*Config, error) {
+	c, err := logbase.Load(path, configChecker{})
+	if err != nil {
+		return nil, err
+	}
+	if err := logbase.ApplyEnv(c, configChecker{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ParseConfig parses and validates a configuration in format "yaml" or
+// "json". Unknown keys are errors, so typos do not pass silently.
+func ParseConfig(data []byte, format string) (*Config, error) {
+	return logbase.Parse(data, format, configChecker{})
+}
+
+// ValidateConfig reports every invalid setting in c.
+func ValidateConfig(c *Config) error {
+	return c.Validate(configChecker{})
+}
+
+// configChecker checks config values against this backend's levels,
+// formats, themes and the like.
+type configChecker struct{}
+
+func (configChecker) Level(name string) error {
+	_, err := ParseLevel(name)
+	return err
+}
+
+func (configChecker) LevelSpec(spec string) error {
+	_, err := ParseLevelSpec(spec)
+	return err
+}
+
+func (configChecker) RateLimits(spec string) error {
+	_, err := ParseRateLimitSpec(spec)
+	return err
+}
+
+func (configChecker) Format(name string) error {
+	if !sinkFormats[name] {
+		return fmt.Errorf("unknown format %q", name)
+	}
+	return nil
+}
+
+func (configChecker) Color(mode string) error {
+	_, err := parseColorMode(mode)
+	return err
+}
+
+func (configChecker) Theme(name string) error {
+	if _, ok := themes[name]; !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	return nil
+}
+
+func (configChecker) PanicPolicy(name string) error {
+	_, err := parsePanicPolicy(name)
+	return err
+}
+
+func (configChecker) Redaction(r *RedactionConfig) error {
+	_, err := newRedactor(redactionOf(r))
+	return err
+}
+
+// redactionOf returns the Redaction of r.
+func redactionOf(r *RedactionConfig) Redaction {
+	var key []byte
+	if r.Key != "" {
+		key = []byte(r.Key)
+	}
+	return Redaction{Mode: r.Mode, Fields: r.Fields, Patterns: r.Patterns, Keep: r.Keep, Key: key}
+}
+
+// samplingOf returns the Sampling of s.
+func samplingOf(s *SamplingConfig) Sampling {
+	return Sampling{Tick: s.TickDuration(), First: s.First, Thereafter: s.Thereafter, Key: s.Key}
+}
+
+// themes are the themes a config file can name.
+var themes = map[string]func() *Theme{
+	"":              DefaultTheme,
+	"default":       DefaultTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// timeFormats maps config names to TimeFormat* constants; anything else is a
+// layout.
+var timeFormats = map[string]string{
+	"default":  TimeFormatDefault,
+	"zap":      TimeFormatZap,
+	"rfc3339":  TimeFormatRFC3339,
+	"epoch_ms": TimeFormatEpochMillis,
+	"epoch_ns": TimeFormatEpochNanos,
+}
+
+// parseColorMode parses a config color setting.
+func parseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return ColorAuto, fmt.Errorf("unknown color mode %q", s)
+}
+
+// parsePanicPolicy parses a config panic_policy setting.
+func parsePanicPolicy(s string) (PanicPolicy, error) {
+	switch strings.ToLower(s) {
+	case "", "repanic":
+		return PanicRepanic, nil
+	case "exit":
+		return PanicExit, nil
+	}
+	return PanicRepanic, fmt.Errorf("unknown panic policy %q", s)
+}
+
+var (
+	// configFiles are the file sinks opened by the last ApplyConfig, or by
+	// Initialize for LOG_OUTPUT.
+	configFilesMutex sync.Mutex
+	configFiles      []io.Closer
+)
+
+// ApplyConfig opens the sinks of c and reconfigures the logger with it,
+// initializing it if needed. File sinks of a previous ApplyConfig are
+// closed once the new ones are in place. The caller setting only applies to
+// the first configuration; a later change is ignored with a warning.
+func ApplyConfig(c *Config) error {
+	if err := ValidateConfig(c); err != nil {
+		return err
+	}
+	level := log.InfoLevel
+	if c.Level != "" {
+		level, _ = ParseLevel(c.Level)
+	}
+	color, _ := parseColorMode(c.Color)
+	panicPolicy, _ := parsePanicPolicy(c.PanicPolicy)
+	opts := []Option{
+		WithComponentLevels(c.Components),
+		WithRateLimits(c.RateLimits),
+		WithColor(color),
+		WithTheme(themes[c.Theme]()),
+		WithPanicPolicy(panicPolicy),
+	}
+	if format, ok := timeFormats[c.Timestamp.Format]; ok {
+		opts = append(opts, WithTimeFormat(format))
+	} else {
+		opts = append(opts, WithTimeFormat(c.Timestamp.Format))
+	}
+	if c.Timestamp.UTC {
+		opts = append(opts, WithUTC())
+	}
+	callerOpts, callerIgnored := callerOnReload(c.Caller)
+	opts = append(opts, callerOpts...)
+	if c.Format != "" {
+		opts = append(opts, WithFormat(c.Format))
+	}
+	if c.Sampling != nil {
+		opts = append(opts, WithSampling(samplingOf(c.Sampling)))
+	}
+	if c.Dedup != nil {
+		hold, _ := c.Dedup.Hold()
+		opts = append(opts, WithDedup(hold))
+	}
+	if c.Redaction != nil {
+		opts = append(opts, WithRedaction(redactionOf(c.Redaction)))
+	}
+
+	sinks, files, err := openSinks(c.Sinks, c.Timestamp.UTC)
+	if err != nil {
+		return err
+	}
+	if len(sinks) > 0 {
+		opts = append(opts, WithSinks(sinks...))
+	}
+	if err := Reconfigure(level, opts...); err != nil {
+		closeFiles(files)
+		return err
+	}
+	replaceConfigFiles(files)
+	if callerIgnored {
+		CfgLog.Warn().Msgf("caller: %t ignored, caller output cannot change after Initialize", c.Caller)
+	}
+	return nil
+}
+
+// openSinks opens the outputs of configs; on error nothing is left open.
+// Rotated file names use UTC if utc is set.
+func openSinks(configs []SinkConfig, utc bool) ([]Sink, []io.Closer, error) {
+	writers, files, err := logbase.OpenSinks(configs, utc)
+	if err != nil {
+		return nil, nil, err
+	}
+	sinks := make([]Sink, len(configs))
+	for i, c := range configs {
+		sinks[i] = Sink{Writer: writers[i], Format: c.Format}
+		if c.Level != "" {
+			sinks[i].Level, _ = ParseLevel(c.Level)
+		}
+		sinks[i].Color, _ = parseColorMode(c.Color)
+	}
+	return sinks, files, nil
+}
+
+// replaceConfigFiles records files as the open file sinks and closes the
+// previous ones, which the logger no longer writes to: the reconfiguration
+// has waited for the entries still being written to them.
+func replaceConfigFiles(files []io.Closer) {
+	configFilesMutex.Lock()
+	old := configFiles
+	configFiles = files
+	configFilesMutex.Unlock()
+	closeFiles(old)
+}
+
+func closeFiles(files []io.Closer) {
+	_ = logbase.CloseSinks(context.Background(), files)
+}
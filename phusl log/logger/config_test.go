@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+const testConfigYAML = `
+level: notice
+components: "PFCP=debug,S*=warn"
+timestamp:
+  format: rfc3339
+  utc: true
+color: never
+theme: high-contrast
+sinks:
+  - type: stdout
+  - type: file
+    path: smf.log
+`
+
+const testConfigJSON = `{
+  "level": "notice",
+  "components": "PFCP=debug,S*=warn",
+  "timestamp": {"format": "rfc3339", "utc": true},
+  "color": "never",
+  "theme": "high-contrast",
+  "sinks": [{"type": "stdout"}, {"type": "file", "path": "smf.log"}]
+}`
+
+func TestParseConfig(t *testing.T) {
+	y, err := ParseConfig([]byte(testConfigYAML), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := ParseConfig([]byte(testConfigJSON), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(y.Sinks) != 2 || y.Sinks[1].Path != "smf.log" || !y.Timestamp.UTC || y.Theme != "high-contrast" {
+		t.Errorf("yaml config = %+v", y)
+	}
+	if y.Level != j.Level || y.Components != j.Components || y.Timestamp != j.Timestamp || len(j.Sinks) != 2 || j.Sinks[1] != y.Sinks[1] {
+		t.Errorf("yaml and json differ:\n%+v\n%+v", y, j)
+	}
+
+	if _, err := ParseConfig([]byte("levle: info"), "yaml"); err == nil {
+		t.Errorf("unknown keys should be rejected")
+	}
+	_, err = ParseConfig([]byte("level: loud\ncolor: pink\nsinks: [{type: file}, {type: syslog}]\nsampling: {tick: soon}\ndedup: {max_hold: -1s}\nredaction: {mode: hash}\npanic_policy: never"), "yaml")
+	if err == nil {
+		t.Fatal("invalid settings should be rejected")
+	}
+	for _, want := range []string{`level: unknown level "loud"`, `color: unknown color mode "pink"`, "sinks[0]: file sink needs a path", `sinks[1]: unknown type "syslog"`, `sampling: tick: invalid duration "soon"`, `dedup: max_hold: invalid duration "-1s"`, "redaction: hash mode needs a key", `panic_policy: unknown panic policy "never"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logger.json")
+	data := strings.Replace(testConfigJSON, `{"type": "stdout"}, `, "", 1)
+	data = strings.Replace(data, "smf.log", filepath.Join(dir, "smf.log"), 1)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = SetComponentLevels("")
+		_ = Reconfigure(log.InfoLevel, WithWriter(io.Discard))
+	})
+
+	NewComponentLogger("PFCP").Debug().Msg("kept")
+	NewComponentLogger("SBI").Notice().Msg("filtered")
+	Lopu.Info().Msg("filtered")
+	Lopu.Warn().Msg("kept")
+
+	out, err := os.ReadFile(filepath.Join(dir, "smf.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if strings.Count(got, "kept") != 2 || strings.Contains(got, "filtered") || strings.Contains(got, "\033") || !strings.Contains(got, "Z | DEBUG | PFCP  | kept") {
+		t.Errorf("file sink:\n%s", got)
+	}
+
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("a missing file should fail")
+	}
+}
+
+func TestApplyConfigReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smf.log")
+	c := &Config{Sinks: []SinkConfig{{Type: "file", Path: path}}}
+	if err := ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = Reconfigure(log.InfoLevel, WithWriter(io.Discard))
+	})
+
+	const writers, lines = 4, 500
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				Lopu.Info().Int("line", j).Msg("reload")
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err := ApplyConfig(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	if err := Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(out), "reload"); got != writers*lines {
+		t.Errorf("%d lines written, want %d", got, writers*lines)
+	}
+}
+
+func TestApplyConfigCallerIgnored(t *testing.T) {
+	if err := Reconfigure(log.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "smf.log")
+	c := &Config{Caller: true, Components: "PFCP=debug", Sinks: []SinkConfig{{Type: "file", Path: path}}}
+	if err := ApplyConfig(c); err != nil {
+		t.Fatalf("a caller change should not fail the reload: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = SetComponentLevels("")
+		_ = Reconfigure(log.InfoLevel, WithWriter(io.Discard))
+	})
+	NewComponentLogger("PFCP").Debug().Msg("applied")
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if !strings.Contains(got, "CFG   | caller: true ignored") || !strings.Contains(got, "PFCP  | applied") || strings.Contains(got, "config_test.go") {
+		t.Errorf("reload with caller: true:\n%s", got)
+	}
+}
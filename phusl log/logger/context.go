@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// context.Context Fields
+// -------------------------------------------------------------
+
+// contextKey holds the log fields of a context.Context.
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying keysAndValues, such as
+// "request_id", id, "UE_ID", ue, in addition to the fields of ctx itself.
+// Entries started from ComponentLogger.Ctx of the returned context get them
+// all. They are encoded once, here.
+func WithContext(ctx context.Context, keysAndValues ...any) context.Context {
+	prev := ContextFields(ctx)
+	fields := log.NewContext(prev[:len(prev):len(prev)]).KeysAndValues(keysAndValues...).Value()
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// ContextFields returns the encoded fields stored in ctx by WithContext.
+func ContextFields(ctx context.Context) log.Context {
+	fields, _ := ctx.Value(contextKey{}).(log.Context)
+	return fields
+}
+
+// ContextLogger is a ComponentLogger adding the fields of a context.Context
+// to every entry; see ComponentLogger.Ctx. Its level methods are those of
+// the component logger, given the fields.
+type ContextLogger struct {
+	c      *ComponentLogger
+	fields log.Context
+}
+
+// Ctx returns a logger for this component that adds the fields stored in ctx
+// by WithContext to every entry, e.g. PfcpLog.Ctx(ctx).Info().Msg("...").
+// It is a small value, cheap enough to build per call.
+func (c *ComponentLogger) Ctx(ctx context.Context) ContextLogger {
+	return ContextLogger{c: c, fields: ContextFields(ctx)}
+}
+
+// Trace starts a new message with trace level.
+func (l ContextLogger) Trace() *log.Entry {
+	return l.c.entry(log.TraceLevel, l.fields)
+}
+
+// Debug starts a new message with debug level.
+func (l ContextLogger) Debug() *log.Entry {
+	return l.c.entry(log.DebugLevel, l.fields)
+}
+
+// Info starts a new message with info level.
+func (l ContextLogger) Info() *log.Entry {
+	return l.c.entry(log.InfoLevel, l.fields)
+}
+
+// Warn starts a new message with warning level.
+func (l ContextLogger) Warn() *log.Entry {
+	return l.c.entry(log.WarnLevel, l.fields)
+}
+
+// Notice starts a new message with notice level.
+func (l ContextLogger) Notice() *log.Entry {
+	return l.c.entry(NoticeLevel, l.fields)
+}
+
+// Error starts a new message with error level.
+func (l ContextLogger) Error() *log.Entry {
+	return l.c.entry(log.ErrorLevel, l.fields)
+}
+
+// Fatal starts a new message with fatal level.
+func (l ContextLogger) Fatal() *log.Entry {
+	return l.c.entry(log.FatalLevel, l.fields)
+}
+
+// Panic starts a new message with panic level.
+func (l ContextLogger) Panic() *log.Entry {
+	return l.c.entry(log.PanicLevel, l.fields)
+}
+
+// WithLevel starts a new message with a built-in or registered level.
+func (l ContextLogger) WithLevel(level log.Level) *log.Entry {
+	return l.c.entry(level, l.fields)
+}
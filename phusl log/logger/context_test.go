@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	ctx := WithContext(context.Background(), "request_id", "req-7", "UE_ID", 1001)
+	session := WithContext(ctx, "pdu_session_id", 5)
+	pfcp := NewComponentLogger("PFCP")
+
+	pfcp.Ctx(session).Info().Str("seid", "42").Msg("Session established")
+	pfcp.Ctx(ctx).Warn().Msg("Session released")
+	pfcp.Ctx(context.Background()).Info().Msg("no fields")
+	pfcp.Ctx(session).Debug().Msg("filtered out")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"| PFCP  | Session established request_id=req-7 UE_ID=1001 pdu_session_id=5 seid=42",
+		"| PFCP  | Session released request_id=req-7 UE_ID=1001",
+		"| PFCP  | no fields",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestContextLoggerCaller(t *testing.T) {
+	var buf bytes.Buffer
+	savedDepth := callerDepth
+	defer func() { callerDepth = savedDepth }()
+	useLevel(t, &buf, log.InfoLevel)
+	callerDepth = 1
+
+	ctx := WithContext(context.Background(), "request_id", "req-7")
+	NewComponentLogger("PFCP").Ctx(ctx).Info().Msg("with caller")
+
+	if got := buf.String(); !strings.Contains(got, " | PFCP  | logger/context_test.go:") {
+		t.Errorf("caller column missing or wrong: %q", got)
+	}
+}
+
+// BenchmarkContextLogger logs with two context fields, building the
+// ContextLogger per call as a handler would.
+func BenchmarkContextLogger(b *testing.B) {
+	saved := globalLogger
+	b.Cleanup(func() { globalLogger = saved })
+	globalLogger = log.Logger{Level: log.InfoLevel, Writer: &log.IOWriter{Writer: io.Discard}}
+	pfcp := NewComponentLogger("PFCP")
+	ctx := WithContext(context.Background(), "request_id", "req-7", "UE_ID", 1001)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pfcp.Ctx(ctx).Info().Msg("Packet processed successfully")
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Repeated-Message Suppression
+// -------------------------------------------------------------
+
+// DefaultDedupHold is the longest a run of repeats is held back when
+// WithDedup is given no hold time.
+const DefaultDedupHold = 10 * time.Second
+
+// WithDedup collapses identical consecutive entries (same component, level,
+// message and fields; the time may differ): the first is logged, the
+// repeats are counted and reported as one "last message repeated N times
+// over D" entry when a different entry arrives, after maxHold
+// (DefaultDedupHold if zero) while repeats continue, or at Reconfigure and
+// Close.
+func WithDedup(maxHold time.Duration) Option {
+	return func(o *options) {
+		if maxHold <= 0 {
+			maxHold = DefaultDedupHold
+		}
+		o.dedup = maxHold
+	}
+}
+
+// repeatedMessage is the message of a repeat summary.
+func repeatedMessage(n int, d time.Duration) string {
+	if d >= time.Millisecond {
+		d = d.Round(time.Millisecond)
+	} else {
+		d = d.Round(time.Microsecond)
+	}
+	if n == 1 {
+		return fmt.Sprintf("last message repeated 1 time over %s", d)
+	}
+	return fmt.Sprintf("last message repeated %d times over %s", n, d)
+}
+
+// activeDeduper is the dedup stage of the last configure, if any; guarded by
+// configMutex.
+var activeDeduper *deduper
+
+// deduper drops entries equal to the previous one and writes repeat
+// summaries to w. Entries are compared as phuslu encoded them, without the
+// leading time field.
+type deduper struct {
+	w    log.Writer
+	hold time.Duration
+
+	mu      sync.Mutex
+	last    []byte    // previous entry without its time
+	level   log.Level // of last
+	first   time.Time // when last was logged
+	latest  time.Time // of the latest repeat
+	repeats int
+	run     uint64 // incremented per run of repeats, to ignore stale timers
+	timer   *time.Timer
+}
+
+func newDeduper(w log.Writer, hold time.Duration) *deduper {
+	return &deduper{w: w, hold: hold}
+}
+
+// WriteEntry implements log.Writer.
+func (d *deduper) WriteEntry(e *log.Entry) (int, error) {
+	buf := e.Value()
+	key := withoutTime(buf)
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e.Level == log.FatalLevel || e.Level == log.PanicLevel {
+		// never held back, and a summary at these levels would exit
+		d.flushLocked()
+		d.last = d.last[:0]
+		return d.w.WriteEntry(e)
+	}
+	if len(d.last) > 0 && bytes.Equal(key, d.last) {
+		d.repeats++
+		d.latest = now
+		if d.repeats == 1 {
+			d.run++
+			run := d.run
+			d.timer = time.AfterFunc(d.hold, func() { d.expire(run) })
+		}
+		return len(buf), nil
+	}
+	d.flushLocked()
+	d.last = append(d.last[:0], key...)
+	d.level, d.first = e.Level, now
+	return d.w.WriteEntry(e)
+}
+
+// expire writes the summary of a run still pending after the hold time.
+// Repeats after it keep being counted, for the next summary.
+func (d *deduper) expire(run uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.run == run && d.repeats > 0 {
+		d.flushLocked()
+	}
+}
+
+// flush writes the summary of pending repeats, if any.
+func (d *deduper) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushLocked()
+}
+
+func (d *deduper) flushLocked() {
+	if d.repeats == 0 {
+		return
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	l := log.Logger{Level: log.TraceLevel, Writer: d.w}
+	e := levelEntry(&l, d.level)
+	if c := jsonFieldValue(d.last, componentKey); c != nil {
+		e = e.RawJSON("component", c)
+	}
+	e.Msg(repeatedMessage(d.repeats, d.latest.Sub(d.first)))
+	d.repeats = 0
+	d.first = d.latest
+}
+
+// componentKey starts the component field of an entry.
+var componentKey = []byte(`,"component":`)
+
+// withoutTime returns entry without its leading `"time":...,` field, whose
+// value is a quoted timestamp or a number and so holds no comma.
+func withoutTime(entry []byte) []byte {
+	const prefix = `{"time":`
+	if !bytes.HasPrefix(entry, []byte(prefix)) {
+		return entry
+	}
+	if i := bytes.IndexByte(entry[len(prefix):], ','); i >= 0 {
+		return entry[len(prefix)+i:]
+	}
+	return entry
+}
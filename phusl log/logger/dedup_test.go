@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestDedup(t *testing.T) {
+	var out syncBuffer
+	if err := Reconfigure(log.InfoLevel, WithColor(ColorNever), WithWriter(&out), WithDedup(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+
+	for i := 0; i < 5; i++ {
+		SBILog.Warn().Str("nf", "AMF").Msg("Retrying NF registration")
+	}
+	SBILog.Warn().Str("nf", "UDM").Msg("Retrying NF registration")
+	PfcpLog.Info().Msg("Association setup")
+	PfcpLog.Info().Msg("Association setup")
+	NfLog.Notice().Msg("Profile updated")
+	NfLog.Notice().Msg("Profile updated")
+	if err := Reconfigure(log.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{
+		`\| WARN  \| SBI   \| Retrying NF registration nf=AMF$`,
+		`\| WARN  \| SBI   \| last message repeated 4 times over \d+(\.\d+)?[µnm]?s$`,
+		`\| WARN  \| SBI   \| Retrying NF registration nf=UDM$`,
+		`\| INFO  \| PFCP  \| Association setup$`,
+		`\| INFO  \| PFCP  \| last message repeated 1 time over`,
+		`\| NOTE  \| NF    \| Profile updated$`,
+		`\| NOTE  \| NF    \| last message repeated 1 time over`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, w := range want {
+		if !regexp.MustCompile(w).MatchString(lines[i]) {
+			t.Errorf("line %d = %q, want match for %s", i, lines[i], w)
+		}
+	}
+}
+
+func TestDedupHold(t *testing.T) {
+	var out syncBuffer
+	d := newDeduper(newSinkWriter(buildOptions([]Option{WithColor(ColorNever), WithWriter(&out)})), 20*time.Millisecond)
+	l := log.Logger{Level: log.InfoLevel, Writer: d}
+	for i := 0; i < 3; i++ {
+		l.Error().Str("component", "PFCP").Msg("Heartbeat timeout")
+	}
+	if n := strings.Count(out.String(), "\n"); n != 1 {
+		t.Fatalf("repeats were written before the hold time:\n%s", out.String())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "repeated 2 times") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "| ERR   | PFCP  | last message repeated 2 times over") {
+		t.Fatalf("no summary after the hold time:\n%s", out.String())
+	}
+
+	// the run continues: later repeats are counted for the next summary
+	l.Error().Str("component", "PFCP").Msg("Heartbeat timeout")
+	d.flush()
+	if !strings.Contains(out.String(), "last message repeated 1 time over") {
+		t.Errorf("repeat after the summary was not counted:\n%s", out.String())
+	}
+}
+
+func TestWithoutTime(t *testing.T) {
+	for _, entry := range []string{
+		`{"time":"2026-10-17T05:22:23.348+05:30","level":"info","message":"x"}`,
+		`{"time":1792197143348,"level":"info","message":"x"}`,
+	} {
+		if got := string(withoutTime([]byte(entry))); got != `,"level":"info","message":"x"}` {
+			t.Errorf("withoutTime(%s) = %s", entry, got)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Error Fields
+// -------------------------------------------------------------
+
+// Keys of the fields added by Err and ErrStack.
+const (
+	ErrorKey      = "error"       // err.Error()
+	ErrorChainKey = "error_chain" // own messages of the wrapped errors, depth first
+	ErrorStackKey = "error_stack" // "function (dir/file.go:line)" frames
+)
+
+// maxErrorChain and maxErrorStack bound the chain and stack of one error.
+const (
+	maxErrorChain = 32
+	maxErrorStack = 32
+)
+
+// Err returns err as fields for Entry.Context: its message under "error",
+// like Entry.Err, and the messages of the errors it wraps, through Unwrap
+// and errors.Join, under "error_chain". JSON sinks get the chain as an
+// array; console sinks print it below the entry, one "caused by" per line:
+//
+//	logger.SessLog.Error().Context(logger.Err(err)).Msg("create SM context failed")
+//
+// A nil err adds nothing.
+func Err(err error) log.Context {
+	if err == nil {
+		return nil
+	}
+	e := log.NewContext(nil).Str(ErrorKey, err.Error())
+	if chain := errorChain(err); len(chain) > 0 {
+		e = e.Strs(ErrorChainKey, chain)
+	}
+	return e.Value()
+}
+
+// ErrStack is Err plus the stack of its caller under "error_stack", for
+// errors worth the cost of capturing it.
+func ErrStack(err error) log.Context {
+	if err == nil {
+		return nil
+	}
+	return log.NewContext(Err(err)).Strs(ErrorStackKey, callerStack(2)).Value()
+}
+
+// errorChain returns the causes of err, depth first: the own message of
+// each error it wraps through Unwrap or errors.Join, leaving out the text of
+// that error's causes, which follow it in the chain. So
+// fmt.Errorf("dial upf: %w", err) contributes "dial upf", and an
+// errors.Join, whose message is only those of its errors, contributes none.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		for _, e := range unwrapErrors(err) {
+			if e == nil || len(chain) == maxErrorChain {
+				continue
+			}
+			if msg := ownMessage(e); msg != "" {
+				chain = append(chain, msg)
+			}
+			walk(e)
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// unwrapErrors returns the errors err wraps, which may include nils.
+func unwrapErrors(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// ownMessage returns the message of err up to where that of the first
+// error it wraps begins, without the ": " before it: "dial upf" for
+// fmt.Errorf("dial upf: %w", err), nothing for errors.Join. A message not
+// containing its cause's is returned whole.
+func ownMessage(err error) string {
+	msg := err.Error()
+	for _, e := range unwrapErrors(err) {
+		if e == nil {
+			continue
+		}
+		if i := strings.Index(msg, e.Error()); i >= 0 {
+			return strings.TrimSuffix(msg[:i], ": ")
+		}
+		break
+	}
+	return msg
+}
+
+// callerStack returns the stack above skip frames as
+// "function (dir/file.go:line)" strings, without the frames of the runtime,
+// such as runtime.main and runtime.goexit.
+func callerStack(skip int) []string {
+	var pcs [maxErrorStack]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip+1, pcs[:])])
+	var stack []string
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") {
+			stack = append(stack, f.Function+" ("+trimPath(f.File)+":"+strconv.Itoa(f.Line)+")")
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// trimPath shortens a file path to its directory and file, "dir/file.go".
+func trimPath(file string) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			return file[j+1:]
+		}
+	}
+	return file
+}
+
+// isErrorLines reports whether the console prints a field on lines of
+// its own rather than as key=value.
+func isErrorLines(key string, valueType byte) bool {
+	return (key == ErrorChainKey || key == ErrorStackKey) && valueType != 's'
+}
+
+// errorLineBreak continues a message spanning lines, such as an errors.Join
+// wrapped in other text, below its "caused by" line.
+const errorLineBreak = "\n        "
+
+// appendErrorLines appends the error chains and stacks of args, one
+// indented line each, below a console entry. The values are JSON arrays of
+// strings, decoded in place.
+func appendErrorLines(b []byte, args *log.FormatterArgs) []byte {
+	for _, kv := range args.KeyValues {
+		if !isErrorLines(kv.Key, kv.ValueType) {
+			continue
+		}
+		prefix := "    caused by: "
+		if kv.Key == ErrorStackKey {
+			prefix = "    at "
+		}
+		for v := kv.Value; ; {
+			i := strings.IndexByte(v, '"')
+			if i < 0 {
+				break
+			}
+			b = append(b, prefix...)
+			b, v = appendErrorLine(b, v[i+1:])
+			b = append(b, '\n')
+		}
+	}
+	return b
+}
+
+// appendErrorLine appends the JSON string opening s, its opening quote
+// already consumed, unescaped and with line breaks continued by
+// errorLineBreak. It returns what follows the closing quote.
+func appendErrorLine(b []byte, s string) ([]byte, string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return append(b, s[start:i]...), s[i+1:]
+		case '\\':
+			b = append(b, s[start:i]...)
+			if i++; i == len(s) {
+				return b, ""
+			}
+			switch c := s[i]; c {
+			case 'n':
+				b = append(b, errorLineBreak...)
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'u':
+				r, ok := hexRune(s[i+1:])
+				if !ok {
+					b = utf8.AppendRune(b, utf8.RuneError)
+					break
+				}
+				i += 4
+				if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], `\u`) {
+					if r2, ok := hexRune(s[i+3:]); ok {
+						r = utf16.DecodeRune(r, r2)
+						i += 6
+					}
+				}
+				b = utf8.AppendRune(b, r)
+			default: // '"', '\\' and '/'
+				b = append(b, c)
+			}
+			start = i + 1
+		}
+	}
+	return append(b, s[start:]...), ""
+}
+
+// hexRune decodes the four hex digits starting s, as in a JSON \u escape.
+func hexRune(s string) (rune, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+	var r rune
+	for _, c := range []byte(s[:4]) {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c -= 'a' - 10
+		case 'A' <= c && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
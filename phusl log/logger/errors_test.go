@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+var errRefused = errors.New("connection refused")
+
+func TestErrorChain(t *testing.T) {
+	dial := fmt.Errorf("dial upf-1: %w", errRefused)
+	for _, tt := range []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"plain", errRefused, nil},
+		{"single", fmt.Errorf("create SM context: %w", dial), []string{"dial upf-1", "connection refused"}},
+		{"bare wrap", fmt.Errorf("%w", errRefused), []string{"connection refused"}},
+		{"other text", fmt.Errorf("%w (retrying)", errRefused), []string{"connection refused"}},
+		{"join", errors.Join(dial, errors.New("upf-2 unreachable")), []string{"dial upf-1", "connection refused", "upf-2 unreachable"}},
+		{"wrapped join", fmt.Errorf("PFCP association: %w", errors.Join(dial, nil)), []string{"dial upf-1", "connection refused"}},
+		{"multi %w", fmt.Errorf("N4: %w", fmt.Errorf("upfs: %w, %w", dial, errRefused)), []string{"upfs", "dial upf-1", "connection refused", "connection refused"}},
+		{"opaque", fmt.Errorf("N4: %w", wrapped{errRefused}), []string{"upf down", "connection refused"}},
+	} {
+		if got := errorChain(tt.err); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: errorChain = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// wrapped is an error whose message does not repeat its cause's.
+type wrapped struct{ err error }
+
+func (w wrapped) Error() string { return "upf down" }
+func (w wrapped) Unwrap() error { return w.err }
+
+func TestErrStackRuntimeFrames(t *testing.T) {
+	done := make(chan []string)
+	go func() { done <- callerStack(1) }()
+	stack := <-done
+	if len(stack) == 0 {
+		t.Fatal("empty stack")
+	}
+	for _, f := range stack {
+		if strings.HasPrefix(f, "runtime.") {
+			t.Errorf("stack has runtime frame %q: %q", f, stack)
+		}
+	}
+}
+
+func TestAppendErrorLine(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{`"plain", "next"]`, "plain"},
+		{`"quote \" slash \\ \/ tab\t"]`, "quote \" slash \\ / tab\t"},
+		{`"a\nb"]`, "a" + errorLineBreak + "b"},
+		{`"\u00e9 \u001b \ud83d\ude00 \uzz"]`, "é \x1b 😀 \uFFFDzz"},
+	} {
+		b, rest := appendErrorLine(nil, tt.in[1:])
+		if string(b) != tt.want {
+			t.Errorf("appendErrorLine(%s) = %q, want %q", tt.in, b, tt.want)
+		}
+		if !strings.HasPrefix(rest, ",") && rest != "]" {
+			t.Errorf("appendErrorLine(%s) left %q", tt.in, rest)
+		}
+	}
+}
+
+func TestErrorLinesZeroAlloc(t *testing.T) {
+	args := consoleFormatterArgs()
+	args.KeyValues = append(args.KeyValues,
+		struct {
+			Key       string
+			Value     string
+			ValueType byte
+		}{ErrorChainKey, `["dial upf-1","connection \"refused\"\nby peer"]`, 'a'},
+	)
+	var b []byte
+	b = appendErrorLines(b, args)
+	if want := "    caused by: dial upf-1\n    caused by: connection \"refused\"" + errorLineBreak + "by peer\n"; string(b) != want {
+		t.Errorf("lines = %q, want %q", b, want)
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		b = appendErrorLines(b[:0], args)
+	})
+	if allocs != 0 {
+		t.Errorf("appendErrorLines allocates %v times per line, want 0", allocs)
+	}
+}
+
+func TestErrConsole(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	err := fmt.Errorf("create SM context: %w", fmt.Errorf("dial upf-1: %w", errRefused))
+	NewComponentLogger("SESS").Error().Context(Err(err)).Int("pdu_session_id", 5).Msg("failed")
+	NewComponentLogger("SESS").Error().Context(ErrStack(errRefused)).Msg("stacked")
+	NewComponentLogger("SESS").Error().Context(Err(nil)).Msg("no error")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) < 6 {
+		t.Fatalf("output:\n%s", buf.String())
+	}
+	for i, want := range []string{
+		`| SESS  | failed error="create SM context: dial upf-1: connection refused" pdu_session_id=5`,
+		"    caused by: dial upf-1",
+		"    caused by: connection refused",
+		`| SESS  | stacked error="connection refused"`,
+		"    at bench/logger.TestErrConsole (logger/errors_test.go:",
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "| SESS  | no error") {
+		t.Errorf("last line = %q", last)
+	}
+}
+
+func TestErrJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Reconfigure(log.InfoLevel, WithWriter(&buf), WithFormat(FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+
+	NewComponentLogger("SESS").Error().Context(ErrStack(fmt.Errorf("dial upf-1: %w", errRefused))).Msg("failed")
+
+	var entry struct {
+		Error string   `json:"error"`
+		Chain []string `json:"error_chain"`
+		Stack []string `json:"error_stack"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if entry.Error != "dial upf-1: connection refused" || len(entry.Chain) != 1 || entry.Chain[0] != "connection refused" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if len(entry.Stack) == 0 || !strings.HasPrefix(entry.Stack[0], "bench/logger.TestErrJSON (logger/errors_test.go:") {
+		t.Errorf("stack = %q", entry.Stack)
+	}
+}
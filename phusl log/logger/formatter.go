@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Custom Console Formatter (append-based, zero allocation)
+// -------------------------------------------------------------
+
+// lineBuffer is a pooled scratch buffer holding one formatted line.
+type lineBuffer struct {
+	b []byte
+}
+
+var lineBufferPool = sync.Pool{
+	New: func() any {
+		return &lineBuffer{b: make([]byte, 0, 512)}
+	},
+}
+
+// maxPooledLine keeps unusually large lines from pinning memory in the pool.
+const maxPooledLine = 64 << 10
+
+// consoleFormatter renders entries in the human-readable console format.
+type consoleFormatter struct {
+	time  *timeFormat // nil prints args.Time unchanged
+	color bool        // ANSI-colored level labels
+	theme *theme      // level labels and colors, defaultTheme if nil
+}
+
+// defaultConsoleFormatter is the colored formatter with default settings.
+var defaultConsoleFormatter = &consoleFormatter{color: true}
+
+// customConsoleFormatter formats args with the default settings.
+func customConsoleFormatter(w io.Writer, args *log.FormatterArgs) (int, error) {
+	return defaultConsoleFormatter.Format(w, args)
+}
+
+// Format appends the whole line into a pooled buffer and hands it to w with a
+// single Write, so concurrent lines never interleave.
+//
+// Format: "timestamp | colored-level | [component | ][caller | ]message key=value ..."
+// Example:
+// 2025-03-08T12:34:56.789Z | [GREEN]INFO  | PFCP  | main.go:42 | Hello World seid=42
+func (f *consoleFormatter) Format(w io.Writer, args *log.FormatterArgs) (int, error) {
+	lb := lineBufferPool.Get().(*lineBuffer)
+	lb.b = f.appendLine(lb.b[:0], args)
+	n, err := w.Write(lb.b)
+	if cap(lb.b) <= maxPooledLine {
+		lineBufferPool.Put(lb)
+	}
+	return n, err
+}
+
+// appendLine appends the console rendering of args to b.
+func (f *consoleFormatter) appendLine(b []byte, args *log.FormatterArgs) []byte {
+	th := f.theme
+	if th == nil {
+		th = defaultTheme
+	}
+	colorCode, levelLabel := th.level(args.Level)
+
+	// phuslu/log sets args.Time to an RFC3339 string with milliseconds (e.g. "2025-03-08T12:34:56.789Z").
+	// Unless WithTimeFormat/WithUTC ask otherwise, we print it as-is.
+	if f.time != nil {
+		b = f.time.appendTime(b, args.Time)
+	} else {
+		b = append(b, args.Time...)
+	}
+	b = append(b, " | "...)
+	if f.color {
+		b = append(b, colorReset...)
+		b = append(b, colorCode...)  // e.g. "\033[32m"
+		b = append(b, levelLabel...) // e.g. "INFO "
+		b = append(b, colorReset...)
+	} else {
+		b = append(b, levelLabel...) // files and pipes get the plain label
+	}
+	b = append(b, " | "...)
+
+	// Component loggers attach a "component" field; plain Lopu entries have none.
+	if component := args.Get("component"); component != "" {
+		if componentColor := th.components[component]; f.color && componentColor != "" {
+			b = append(b, componentColor...)
+			b = appendPadded(b, component, 5)
+			b = append(b, colorReset...)
+		} else {
+			b = appendPadded(b, component, 5) // e.g. "PFCP ", padded like zap's customComponentEncoder
+		}
+		b = append(b, " | "...)
+	}
+	if args.Caller != "" {
+		b = append(b, args.Caller...) // e.g. "nf/handler.go:42", only with WithCaller
+		b = append(b, " | "...)
+	}
+	b = append(b, args.Message...) // e.g. "Hello World"
+
+	b = appendKeyValues(b, args)
+	b = append(b, '\n')
+	b = appendErrorLines(b, args) // chains and stacks of Err and ErrStack
+	if args.Stack != "" {
+		// goroutine stacks, e.g. from RecoverAndLog, as printed by the runtime
+		b = append(b, args.Stack...)
+		if b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+	}
+	return b
+}
+
+// appendPadded appends s left-aligned in a column of width runes, like "%-5s".
+func appendPadded(b []byte, s string, width int) []byte {
+	b = append(b, s...)
+	for n := utf8.RuneCountInString(s); n < width; n++ {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// appendKeyValues renders the structured fields of an entry as ` key=value`
+// pairs, skipping "component" which already has its own column and the
+// error chains and stacks printed below the entry.
+func appendKeyValues(b []byte, args *log.FormatterArgs) []byte {
+	for _, kv := range args.KeyValues {
+		if kv.Key == "component" || isErrorLines(kv.Key, kv.ValueType) {
+			continue
+		}
+		b = append(b, ' ')
+		b = append(b, kv.Key...)
+		b = append(b, '=')
+		if kv.ValueType == 's' && needsQuote(kv.Value) {
+			b = strconv.AppendQuote(b, kv.Value)
+		} else {
+			// numbers, booleans, null and raw JSON objects/arrays print as-is
+			b = append(b, kv.Value...)
+		}
+	}
+	return b
+}
+
+// needsQuote reports whether a string value must be quoted to stay
+// unambiguous in `key=value` form (empty, whitespace, quotes, '=' or control bytes).
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c == '"' || c == '=' || c == '\\' || c == 0x7f {
+			return true
+		}
+		if c >= utf8.RuneSelf {
+			// only quote non-ASCII text that is invalid or unprintable
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError || !unicode.IsPrint(r) {
+				return true
+			}
+			i += size - 1
+		}
+	}
+	return false
+}
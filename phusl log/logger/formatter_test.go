@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestConsoleFormatterKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	l := captureLogger(&buf, log.InfoLevel)
+
+	l.Info().
+		Str("component", "SESS").
+		Int("ue_id", 1001).
+		Bool("ok", true).
+		Str("peer", "10.0.0.1:8805").
+		Str("reason", `no "route" found`).
+		Str("empty", "").
+		Str("nl", "a\nb").
+		Msg("Session created")
+
+	want := ` | SESS  | Session created ue_id=1001 ok=true peer=10.0.0.1:8805 reason="no \"route\" found" empty="" nl="a\nb"` + "\n"
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("got  %q\nwant suffix %q", got, want)
+	}
+}
+
+func TestNeedsQuote(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want bool
+	}{
+		{"plain", false},
+		{"imsi-001010000000001", false},
+		{"héllo", false},
+		{"", true},
+		{"two words", true},
+		{"a=b", true},
+		{`back\slash`, true},
+		{"tab\there", true},
+		{"\xff", true},
+	} {
+		if got := needsQuote(tc.in); got != tc.want {
+			t.Errorf("needsQuote(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// levelToColor is the hard-coded level mapping DefaultTheme replaced.
+func levelToColor(level string) (colorCode, label string) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return colorCyan, "DEBUG"
+	case "info":
+		return colorGreen, "INFO "
+	case "warn":
+		return colorYellow, "WARN "
+	case "error":
+		return colorRed, "ERR  "
+	case "fatal":
+		return "\033[41m\033[37m", "FATAL"
+	default:
+		return colorWhite, strings.ToUpper(level)
+	}
+}
+
+// fmtConsoleFormatter is the previous fmt-based formatter, kept as the
+// reference the append-based one must match byte for byte.
+func fmtConsoleFormatter(w io.Writer, args *log.FormatterArgs) (int, error) {
+	colorCode, levelLabel := levelToColor(args.Level)
+	var b []byte
+	b = fmt.Appendf(b, "%s | \033[0m%s%s\033[0m | ", args.Time, colorCode, levelLabel)
+	if component := args.Get("component"); component != "" {
+		b = fmt.Appendf(b, "%-5s | ", component)
+	}
+	if args.Caller != "" {
+		b = fmt.Appendf(b, "%s | ", args.Caller)
+	}
+	b = append(b, args.Message...)
+	b = appendKeyValues(b, args)
+	b = append(b, '\n')
+	return w.Write(b)
+}
+
+func TestConsoleFormatterMatchesFmt(t *testing.T) {
+	var got, want bytes.Buffer
+	entries := []func(l *log.Logger){
+		func(l *log.Logger) { l.Info().Msg("Packet processed successfully UE_ID 1001 iteration") },
+		func(l *log.Logger) { l.Error().Str("component", "PFCP").Int("seid", 7).Msg("Association lost") },
+		func(l *log.Logger) { l.Warn().Str("component", "CHARGE").Str("reason", "quota exhausted").Msg("") },
+		func(l *log.Logger) { l.Debug().Str("component", "SBI").Caller(1).Msg("NF discovery") },
+		func(l *log.Logger) { l.Error().Str("component", "µS").Msg("unicode component") },
+	}
+	for i, entry := range entries {
+		got.Reset()
+		want.Reset()
+		gl := log.Logger{Level: log.DebugLevel, Writer: &log.ConsoleWriter{Formatter: customConsoleFormatter, Writer: &got}}
+		wl := log.Logger{Level: log.DebugLevel, Writer: &log.ConsoleWriter{Formatter: fmtConsoleFormatter, Writer: &want}}
+		entry(&gl)
+		entry(&wl)
+		// the timestamps differ by at most a millisecond, so compare past them
+		g, w := got.String(), want.String()
+		g, w = g[strings.IndexByte(g, '|'):], w[strings.IndexByte(w, '|'):]
+		if g != w {
+			t.Errorf("entry %d:\n got  %q\n want %q", i, g, w)
+		}
+	}
+}
+
+func consoleFormatterArgs() *log.FormatterArgs {
+	args := &log.FormatterArgs{
+		Time:    "2025-03-08T12:34:56.789Z",
+		Level:   "info",
+		Message: "Packet processed successfully",
+	}
+	args.KeyValues = append(args.KeyValues,
+		struct {
+			Key       string
+			Value     string
+			ValueType byte
+		}{"component", "MAIN", 's'},
+		struct {
+			Key       string
+			Value     string
+			ValueType byte
+		}{"UE_ID", "1001", 'n'},
+	)
+	return args
+}
+
+func TestConsoleFormatterZeroAlloc(t *testing.T) {
+	args := consoleFormatterArgs()
+	allocs := testing.AllocsPerRun(1000, func() {
+		_, _ = customConsoleFormatter(io.Discard, args)
+	})
+	if allocs != 0 {
+		t.Errorf("customConsoleFormatter allocates %v times per line, want 0", allocs)
+	}
+}
+
+func BenchmarkConsoleFormatter(b *testing.B) {
+	args := consoleFormatterArgs()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = customConsoleFormatter(io.Discard, args)
+	}
+}
+
+func BenchmarkConsoleFormatterFmt(b *testing.B) {
+	args := consoleFormatterArgs()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = fmtConsoleFormatter(io.Discard, args)
+	}
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"unicode/utf8"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// JSON Format (shared with the zap bench/logger)
+// -------------------------------------------------------------
+
+// JSON keys of the FormatJSON sinks. The zap bench/logger writes the same
+// keys in the same order, with the same level names and time layout:
+//
+//	{"level":"info","time":"2026-10-17T05:22:23.348Z","component":"PFCP","caller":"smf/pfcp.go:42","message":"Packet processed","seid":42}
+//
+// Fields follow the message; "stack" comes last when present.
+const (
+	JSONLevelKey     = "level"
+	JSONTimeKey      = "time"
+	JSONComponentKey = "component"
+	JSONCallerKey    = "caller"
+	JSONMessageKey   = "message"
+	JSONStackKey     = "stack"
+)
+
+// jsonFormatter renders entries in the shared JSON format. phuslu/log's own
+// encoding is close, but puts the time first and adds "callerfunc" and
+// "goid" with WithCaller.
+type jsonFormatter struct {
+	time *timeFormat // RFC3339 with milliseconds, in UTC with WithUTC
+}
+
+func newJSONFormatter(o options) *jsonFormatter {
+	return &jsonFormatter{time: newTimeFormat(TimeFormatRFC3339, o.utc)}
+}
+
+// appendLine appends the JSON rendering of args to b.
+func (f *jsonFormatter) appendLine(b []byte, args *log.FormatterArgs) []byte {
+	b = append(b, `{"`+JSONLevelKey+`":`...)
+	b = appendJSONString(b, args.Level)
+	b = append(b, `,"`+JSONTimeKey+`":"`...)
+	if f.time != nil {
+		b = f.time.appendTime(b, args.Time)
+	} else {
+		b = append(b, args.Time...)
+	}
+	b = append(b, '"')
+	if component := args.Get("component"); component != "" {
+		b = append(b, `,"`+JSONComponentKey+`":`...)
+		b = appendJSONString(b, component)
+	}
+	if args.Caller != "" {
+		b = append(b, `,"`+JSONCallerKey+`":`...)
+		b = appendJSONString(b, args.Caller)
+	}
+	b = append(b, `,"`+JSONMessageKey+`":`...)
+	b = appendJSONString(b, args.Message)
+	for _, kv := range args.KeyValues {
+		if kv.Key == "component" {
+			continue
+		}
+		b = append(b, ',')
+		b = appendJSONString(b, kv.Key)
+		b = append(b, ':')
+		if kv.ValueType == 's' {
+			b = appendJSONString(b, kv.Value)
+		} else {
+			b = append(b, kv.Value...) // numbers, booleans, null, objects and arrays as encoded
+		}
+	}
+	if args.Stack != "" {
+		b = append(b, `,"`+JSONStackKey+`":`...)
+		b = appendJSONString(b, args.Stack)
+	}
+	return append(b, "}\n"...)
+}
+
+// appendJSONString appends s as a JSON string, escaping like zap's JSON
+// encoder: quotes, backslashes and control bytes, with invalid UTF-8
+// replaced by U+FFFD.
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' && c < utf8.RuneSelf {
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `�`...)
+			i++
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	args := &log.FormatterArgs{
+		Time:    "2026-10-17T05:22:23.348+05:30",
+		Level:   "notice",
+		Caller:  "smf/pfcp.go:42",
+		Goid:    "7",
+		Message: "Packet processed",
+	}
+	args.KeyValues = append(args.KeyValues,
+		struct {
+			Key, Value string
+			ValueType  byte
+		}{"component", "PFCP", 's'},
+		struct {
+			Key, Value string
+			ValueType  byte
+		}{"seid", "42", 'n'},
+		struct {
+			Key, Value string
+			ValueType  byte
+		}{"ue", "imsi\t\"001\"\x01", 's'},
+	)
+
+	got := string(newJSONFormatter(options{utc: true}).appendLine(nil, args))
+	want := `{"level":"notice","time":"2026-10-16T23:52:23.348Z","component":"PFCP","caller":"smf/pfcp.go:42","message":"Packet processed","seid":42,"ue":"imsi\t\"001\"\u0001"}` + "\n"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestJSONString(t *testing.T) {
+	for _, s := range []string{"plain", `q"b\`, "nl\n\r\t", "\x00\x1b[31m", "ünï✓", "bad\xffutf8"} {
+		var back string
+		if err := json.Unmarshal(appendJSONString(nil, s), &back); err != nil {
+			t.Errorf("%q: %v", s, err)
+		} else if back != strings.ToValidUTF8(s, "�") {
+			t.Errorf("%q round-trips to %q", s, back)
+		}
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	l := log.Logger{Level: log.TraceLevel, Caller: 1, Writer: newSinkWriter(buildOptions([]Option{WithFormat(FormatJSON), WithWriter(&buf)}))}
+	l.Info().Str("component", "SESS").Int("UE_ID", 1001).Msg("Session created")
+
+	line := buf.String()
+	for _, key := range []string{`{"level":"info","time":"`, `,"component":"SESS","caller":"logger/json_test.go:`, `,"message":"Session created","UE_ID":1001}`} {
+		if !strings.Contains(line, key) {
+			t.Errorf("%s does not contain %s", line, key)
+		}
+	}
+	if strings.Contains(line, "goid") || strings.Contains(line, "callerfunc") {
+		t.Errorf("phuslu-only keys in %s", line)
+	}
+}
+
+func BenchmarkJSONSink(b *testing.B) {
+	var out discardCounter
+	l := log.Logger{Level: log.InfoLevel, Writer: newSinkWriter(buildOptions([]Option{WithFormat(FormatJSON), WithWriter(&out)}))}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info().Str("component", "PFCP").Int("UE_ID", 1001).Msg("Packet processed successfully")
+	}
+}
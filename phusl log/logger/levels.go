@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Registered Levels (NOTICE, ...)
+// -------------------------------------------------------------
+
+// firstCustomLevel is the first log.Level value handed out by RegisterLevel,
+// well clear of phuslu's built-in TraceLevel..PanicLevel.
+const firstCustomLevel log.Level = 16
+
+// NoticeLevel sits between info and warn: normal but significant events.
+var NoticeLevel = RegisterLevel("notice", log.InfoLevel)
+
+// customLevel is one registered level.
+type customLevel struct {
+	name     string
+	severity int
+}
+
+// levelTable is replaced as a whole on registration, so the hot path reads it
+// without locking.
+type levelTable struct {
+	custom []customLevel // indexed by level - firstCustomLevel
+}
+
+var (
+	levels      atomic.Pointer[levelTable]
+	levelsMutex sync.Mutex
+)
+
+// loadLevels returns the current table; it is only empty before package
+// initialization has registered NoticeLevel.
+func loadLevels() *levelTable {
+	if t := levels.Load(); t != nil {
+		return t
+	}
+	return &levelTable{}
+}
+
+// RegisterLevel adds a level named name (lower case, e.g. "notice") that
+// sorts directly above the built-in level above, after any level registered
+// above it before. Log at it with ComponentLogger.WithLevel; it filters and
+// sorts by severity, and themes style it by name.
+func RegisterLevel(name string, above log.Level) log.Level {
+	if above < log.TraceLevel || above > log.PanicLevel {
+		panic(fmt.Sprintf("logger: RegisterLevel(%q): %d is not a built-in level", name, above))
+	}
+	name = strings.ToLower(name)
+
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+
+	old := loadLevels()
+	severity := builtinSeverity(above) + 1
+	for _, c := range old.custom {
+		if c.name == name {
+			panic(fmt.Sprintf("logger: RegisterLevel(%q): already registered", name))
+		}
+		if c.severity >= severity && c.severity < builtinSeverity(above+1) {
+			severity = c.severity + 1
+		}
+	}
+	t := &levelTable{custom: append(old.custom[:len(old.custom):len(old.custom)], customLevel{name, severity})}
+	levels.Store(t)
+	return firstCustomLevel + log.Level(len(t.custom)-1)
+}
+
+// builtinSeverity spaces phuslu's levels 16 apart to leave room for
+// registered levels in between.
+func builtinSeverity(level log.Level) int {
+	return int(level) * 16
+}
+
+// levelSeverity orders built-in and registered levels.
+func levelSeverity(level log.Level) int {
+	if level < firstCustomLevel {
+		return builtinSeverity(level)
+	}
+	if t := loadLevels(); int(level-firstCustomLevel) < len(t.custom) {
+		return t.custom[level-firstCustomLevel].severity
+	}
+	return builtinSeverity(log.PanicLevel) + 15
+}
+
+// nameSeverity is levelSeverity for a "level" field value. Entries without
+// a level, from Logger.Log, sort above every level.
+func nameSeverity(name string) int {
+	switch name {
+	case "trace":
+		return builtinSeverity(log.TraceLevel)
+	case "debug":
+		return builtinSeverity(log.DebugLevel)
+	case "info":
+		return builtinSeverity(log.InfoLevel)
+	case "warn":
+		return builtinSeverity(log.WarnLevel)
+	case "error":
+		return builtinSeverity(log.ErrorLevel)
+	case "fatal":
+		return builtinSeverity(log.FatalLevel)
+	case "panic":
+		return builtinSeverity(log.PanicLevel)
+	}
+	for _, c := range loadLevels().custom {
+		if c.name == name {
+			return c.severity
+		}
+	}
+	return builtinSeverity(log.PanicLevel + 1)
+}
+
+// levelName returns the lower-case name written to the "level" field.
+func levelName(level log.Level) string {
+	if level < firstCustomLevel {
+		return level.String()
+	}
+	if t := loadLevels(); int(level-firstCustomLevel) < len(t.custom) {
+		return t.custom[level-firstCustomLevel].name
+	}
+	return "????"
+}
+
+// ParseLevel converts a built-in or registered level name into a log.Level.
+func ParseLevel(s string) (log.Level, error) {
+	if level := log.ParseLevel(s); level >= log.TraceLevel && level <= log.PanicLevel {
+		return level, nil
+	}
+	name := strings.ToLower(s)
+	for i, c := range loadLevels().custom {
+		if c.name == name {
+			return firstCustomLevel + log.Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("logger: unknown level %q", s)
+}
+
+// builtinThreshold returns the lowest built-in level not below level, which
+// is what phuslu itself filters on for entries started directly on Lopu.
+func builtinThreshold(level log.Level) log.Level {
+	if level < firstCustomLevel {
+		return level
+	}
+	return severityThreshold(levelSeverity(level))
+}
+
+// severityThreshold returns the lowest built-in level at or above severity.
+func severityThreshold(severity int) log.Level {
+	for l := log.TraceLevel; l <= log.PanicLevel; l++ {
+		if builtinSeverity(l) >= severity {
+			return l
+		}
+	}
+	return log.PanicLevel
+}
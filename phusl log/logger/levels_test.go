@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestRegisteredLevelOrder(t *testing.T) {
+	for _, tc := range []struct {
+		lower, higher log.Level
+	}{
+		{log.TraceLevel, log.DebugLevel},
+		{log.InfoLevel, NoticeLevel},
+		{NoticeLevel, log.WarnLevel},
+		{log.FatalLevel, log.PanicLevel},
+	} {
+		if levelSeverity(tc.lower) >= levelSeverity(tc.higher) {
+			t.Errorf("%s should sort below %s", levelName(tc.lower), levelName(tc.higher))
+		}
+	}
+	if got := builtinThreshold(NoticeLevel); got != log.WarnLevel {
+		t.Errorf("builtinThreshold(notice) = %v, want warn", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]log.Level{
+		"trace":  log.TraceLevel,
+		"INFO":   log.InfoLevel,
+		"notice": NoticeLevel,
+		"Notice": NoticeLevel,
+		"panic":  log.PanicLevel,
+	} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("ParseLevel(verbose) should fail")
+	}
+}
+
+func TestRegisterLevelDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering notice twice should panic")
+		}
+	}()
+	RegisterLevel("NOTICE", log.InfoLevel)
+}
+
+func TestComponentLoggerNotice(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	sess := NewComponentLogger("SESS")
+	sess.Notice().Msg("Session modified")
+	sess.Trace().Msg("filtered out")
+	if got := buf.String(); !strings.Contains(got, "\033[34mNOTE \033[0m | SESS  | Session modified\n") {
+		t.Errorf("notice line = %q", got)
+	}
+
+	buf.Reset()
+	useLevel(t, &buf, NoticeLevel)
+	sess.Info().Msg("filtered out")
+	sess.Notice().Msg("kept")
+	sess.Warn().Msg("kept")
+	if got := strings.Count(buf.String(), "kept"); got != 2 || strings.Contains(buf.String(), "filtered") {
+		t.Errorf("notice threshold output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Per-Component Levels
+// -------------------------------------------------------------
+
+// LevelSpec sets per-component levels, parsed from a spec such as
+// "PFCP=debug,SESS=debug,*=info". Exact component names win over patterns,
+// patterns (path.Match syntax, e.g. "S*" or "GS?") apply in the order given,
+// and "*" sets the level of every other component. Components matching
+// nothing follow the global level.
+type LevelSpec struct {
+	exact    map[string]log.Level
+	patterns []levelRule
+	fallback *log.Level // "*"
+	spec     string
+}
+
+// levelRule is one "pattern=level" entry.
+type levelRule struct {
+	pattern string
+	level   log.Level
+}
+
+// ParseLevelSpec parses a comma-separated list of component=level entries.
+// Levels may be built-in or registered; an empty spec clears all overrides.
+func ParseLevelSpec(spec string) (*LevelSpec, error) {
+	s := &LevelSpec{exact: make(map[string]log.Level), spec: spec}
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, levelName, ok := strings.Cut(entry, "=")
+		name, levelName = strings.TrimSpace(name), strings.TrimSpace(levelName)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("logger: level spec entry %q: want component=level", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("logger: level spec entry %q: %s is listed twice", entry, name)
+		}
+		seen[name] = true
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("logger: level spec entry %q: unknown level %q", entry, levelName)
+		}
+		switch {
+		case name == "*":
+			s.fallback = &level
+		case strings.ContainsAny(name, "*?[\\"):
+			if _, err := path.Match(name, ""); err != nil {
+				return nil, fmt.Errorf("logger: level spec entry %q: bad pattern %q", entry, name)
+			}
+			s.patterns = append(s.patterns, levelRule{name, level})
+		default:
+			s.exact[name] = level
+		}
+	}
+	return s, nil
+}
+
+// String returns the spec as parsed.
+func (s *LevelSpec) String() string {
+	return s.spec
+}
+
+// levelFor returns the level of component, or def if the spec has none.
+func (s *LevelSpec) levelFor(component string, def log.Level) log.Level {
+	if s == nil {
+		return def
+	}
+	if level, ok := s.exact[component]; ok {
+		return level
+	}
+	for _, r := range s.patterns {
+		if ok, _ := path.Match(r.pattern, component); ok {
+			return r.level
+		}
+	}
+	if s.fallback != nil {
+		return *s.fallback
+	}
+	return def
+}
+
+var (
+	// levelStateMutex guards the global level, the component spec and the
+	// component loggers they are applied to, by name.
+	levelStateMutex sync.Mutex
+	defaultLevel    log.Level
+	componentSpec   *LevelSpec
+	components      = make(map[string]*ComponentLogger)
+
+	// lowestSeverity is the lowest of the default and component levels, which
+	// globalLogger filters at.
+	lowestSeverity int
+)
+
+// setLevels changes the global level and, if spec is not nil, the
+// per-component levels.
+func setLevels(level log.Level, spec *LevelSpec) {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	defaultLevel = level
+	if spec != nil {
+		componentSpec = spec
+	}
+	applyLevels()
+}
+
+// SetComponentLevels replaces the per-component levels with spec, e.g.
+// "PFCP=debug,SESS=debug,*=info". An empty spec clears them.
+func SetComponentLevels(spec string) error {
+	s, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	componentSpec = s
+	applyLevels()
+	return nil
+}
+
+// ComponentLevels returns the current per-component level spec.
+func ComponentLevels() string {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	return componentSpec.String()
+}
+
+// componentLogger returns the logger of component name, creating it on first
+// use with the level and rate limit that match its name; "" is a logger
+// without a component column.
+func componentLogger(name string) *ComponentLogger {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	if c := components[name]; c != nil {
+		return c
+	}
+	c := &ComponentLogger{component: name, componentState: new(componentState)}
+	if name != "" {
+		c.context = log.NewContext(nil).Str("component", name).Value()
+	}
+	components[name] = c
+	severity := levelSeverity(componentSpec.levelFor(name, defaultLevel))
+	c.minSeverity.Store(int64(severity))
+	if severity < lowestSeverity {
+		lowestSeverity = severity
+		applyThreshold()
+	}
+	applyRateLimit(c)
+	return c
+}
+
+// applyLevels pushes the default level and component spec to every logger;
+// the caller must hold levelStateMutex. globalLogger, shared by all
+// components, filters at the lowest of their levels, and each component then
+// checks its own level before an entry is started.
+func applyLevels() {
+	lowestSeverity = levelSeverity(defaultLevel)
+	for name, c := range components {
+		severity := levelSeverity(componentSpec.levelFor(name, defaultLevel))
+		c.minSeverity.Store(int64(severity))
+		lowestSeverity = min(lowestSeverity, severity)
+	}
+	applyThreshold()
+	if threshold := builtinThreshold(defaultLevel); lopu.Level != threshold {
+		lopu.SetLevel(threshold)
+	}
+}
+
+// applyThreshold sets globalLogger to filter at lowestSeverity; the caller
+// must hold levelStateMutex.
+func applyThreshold() {
+	// phuslu stores the level atomically but reads it without atomics on
+	// amd64 by design, so only store when it actually changes.
+	if threshold := severityThreshold(lowestSeverity); globalLogger.Level != threshold {
+		globalLogger.SetLevel(threshold)
+	}
+}
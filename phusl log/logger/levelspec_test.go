@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	s, err := ParseLevelSpec("PFCP=debug, SESS=notice ,S*=warn,GS?=error,*=info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for component, want := range map[string]log.Level{
+		"PFCP": log.DebugLevel,
+		"SESS": NoticeLevel, // exact names win over patterns
+		"SBI":  log.WarnLevel,
+		"GSM":  log.ErrorLevel,
+		"GIN":  log.InfoLevel,
+	} {
+		if got := s.levelFor(component, log.TraceLevel); got != want {
+			t.Errorf("levelFor(%s) = %v, want %v", component, levelName(got), levelName(want))
+		}
+	}
+	if s, _ := ParseLevelSpec("PFCP=debug"); s.levelFor("GIN", log.WarnLevel) != log.WarnLevel {
+		t.Errorf("components without a rule should follow the global level")
+	}
+
+	for _, bad := range []string{"PFCP", "=debug", "PFCP=loud", "PFCP=debug,PFCP=info", "[=debug"} {
+		if _, err := ParseLevelSpec(bad); err == nil {
+			t.Errorf("ParseLevelSpec(%q) should fail", bad)
+		}
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+	t.Cleanup(func() { _ = SetComponentLevels("") })
+
+	pfcp, gin := NewComponentLogger("PFCP"), NewComponentLogger("GIN")
+	if err := SetComponentLevels("PFCP=debug,*=info"); err != nil {
+		t.Fatal(err)
+	}
+	pfcp.Debug().Msg("kept")
+	gin.Debug().Msg("filtered")
+	Lopu.Debug().Msg("filtered")
+
+	// runtime change, seen by loggers created before it
+	if err := SetComponentLevels("PFCP=error,G*=trace"); err != nil {
+		t.Fatal(err)
+	}
+	pfcp.Warn().Msg("filtered")
+	gin.Trace().Msg("kept")
+
+	got := buf.String()
+	if strings.Count(got, "kept") != 2 || strings.Contains(got, "filtered") {
+		t.Errorf("output:\n%s", got)
+	}
+	if ComponentLevels() != "PFCP=error,G*=trace" {
+		t.Errorf("ComponentLevels() = %q", ComponentLevels())
+	}
+	if err := SetComponentLevels("PFCP=chatty"); err == nil || ComponentLevels() != "PFCP=error,G*=trace" {
+		t.Errorf("an invalid spec should fail and keep the old one: %v", err)
+	}
+}
+
+func TestNewComponentLoggerReuse(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+	t.Cleanup(func() { _ = SetComponentLevels("") })
+	if err := SetComponentLevels("REUSE*=debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new component gets its level on creation, lowering the shared
+	// threshold, and later calls return the same logger
+	c := NewComponentLogger("REUSE1")
+	if again := NewComponentLogger("REUSE1"); again != c {
+		t.Error("NewComponentLogger should return the existing logger of a name")
+	}
+	n := len(components)
+	NewComponentLogger("REUSE1")
+	if len(components) != n {
+		t.Errorf("components grew from %d to %d", n, len(components))
+	}
+	c.Debug().Msg("kept")
+	NewComponentLogger("OTHER").Debug().Msg("filtered")
+
+	if got := buf.String(); !strings.Contains(got, "kept") || strings.Contains(got, "filtered") {
+		t.Errorf("output:\n%s", got)
+	}
+}
+
+func BenchmarkComponentLoggerFiltered(b *testing.B) {
+	useLevel(b, nil, log.InfoLevel)
+	c := NewComponentLogger("GIN")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Debug().Int("UE_ID", 1001).Msg("Packet processed successfully")
+	}
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"bench/logbase"
+)
+
+// -------------------------------------------------------------
+// Flush and Shutdown
+// -------------------------------------------------------------
+
+// Flush writes out everything held back: repeats held by dedup, pending
+// rate-limit drop reports, AsyncWriter queues (until ctx is done) and file
+// buffers. The error reports every line that could not be written.
+func Flush(ctx context.Context) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	return flushLocked(ctx)
+}
+
+// flushLocked is Flush; the caller holds configMutex.
+func flushLocked(ctx context.Context) error {
+	if activeDeduper != nil {
+		activeDeduper.flush()
+	}
+	reportRateLimitDrops()
+	var errs []error
+	for _, s := range current.sinks {
+		errs = append(errs, logbase.FlushWriter(ctx, s.Writer))
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes every sink like Flush and closes the files opened by
+// ApplyConfig or LOG_OUTPUT. Entries logged after Close go to os.Stderr,
+// still redacted, sampled and deduplicated as configured, until Initialize
+// or ApplyConfig configure new sinks.
+func Close(ctx context.Context) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	errs := []error{flushLocked(ctx)}
+	if configured {
+		current.sinks = []Sink{{Writer: os.Stderr}}
+		applyOutputs(current)
+	}
+
+	configFilesMutex.Lock()
+	files := configFiles
+	configFiles = nil
+	configFilesMutex.Unlock()
+	errs = append(errs, logbase.CloseSinks(ctx, files))
+	return errors.Join(errs...)
+}
+
+// CloseOnSignal calls Close with the given timeout when the process gets
+// one of sigs (SIGINT and SIGTERM by default), for programs that stop on
+// their own: handlers registered with signal.Notify get the signal as well.
+// It handles the first signal only. stop cancels it.
+func CloseOnSignal(timeout time.Duration, sigs ...os.Signal) (stop func()) {
+	return closeOnSignal(timeout, false, sigs)
+}
+
+// ExitOnSignal is CloseOnSignal for programs without signal handling of
+// their own: after Close it raises the signal again, so the process stops
+// as it would without the helper.
+func ExitOnSignal(timeout time.Duration, sigs ...os.Signal) (stop func()) {
+	return closeOnSignal(timeout, true, sigs)
+}
+
+func closeOnSignal(timeout time.Duration, reraise bool, sigs []os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-ch:
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			if err := Close(ctx); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			cancel()
+			// only this channel: the default action comes back unless the
+			// program has handlers of its own
+			signal.Stop(ch)
+			if reraise {
+				if p, err := os.FindProcess(os.Getpid()); err == nil {
+					_ = p.Signal(sig)
+				}
+			}
+		case <-stopped:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(stopped)
+		})
+	}
+}
+
+// drainTimeout bounds the wait for entries still being written to outputs
+// replaced by a reconfiguration, so a stuck writer cannot hold it forever.
+const drainTimeout = time.Second
+
+// drain waits until active, a count of entries being written, drops to
+// zero or drainTimeout passes.
+func drain(active *atomic.Int64) {
+	deadline := time.Now().Add(drainTimeout)
+	for active.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Microsecond)
+	}
+}
@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// syncBuffer is a bytes.Buffer safe for the AsyncWriter goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestClose(t *testing.T) {
+	dir := t.TempDir()
+	c, err := ParseConfig([]byte(fmt.Sprintf(`
+sinks:
+  - type: file
+    path: %s
+    async: {queue_size: 8}
+`, filepath.Join(dir, "smf.log"))), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+	for i := 0; i < 100; i++ {
+		PfcpLog.Info().Int("seq", i).Msg("Packet processed successfully")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "smf.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 100 || !strings.HasSuffix(string(data), "Packet processed successfully seq=99\n") {
+		t.Errorf("file has %d lines after Close:\n%s", n, data)
+	}
+	// Close is idempotent: the files are gone, the fallback is stderr
+	if err := Close(ctx); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCloseKeepsWrappers(t *testing.T) {
+	stderr := captureStderr(t)
+	if err := Reconfigure(log.InfoLevel, WithColor(ColorNever), WithWriter(io.Discard),
+		WithRedaction(Redaction{Mode: RedactMask}), WithDedup(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+	if err := Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		PduSessLog.Info().Str("SUPI", "imsi-208930000000003").Msg("late shutdown")
+	}
+
+	got := stderr()
+	if strings.Contains(got, "208930000000003") || !strings.Contains(got, "late shutdown SUPI=imsi-***************") {
+		t.Errorf("entry after Close not redacted:\n%s", got)
+	}
+	if n := strings.Count(got, "late shutdown"); n != 1 {
+		t.Errorf("entry after Close not deduplicated, %d copies:\n%s", n, got)
+	}
+}
+
+// captureStderr points os.Stderr at a file until the test ends; the
+// returned function reads what was written so far.
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() { os.Stderr = saved; f.Close() })
+	return func() string {
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
+
+func TestCloseOnSignal(t *testing.T) {
+	if err := Reconfigure(log.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+	app := make(chan os.Signal, 1)
+	signal.Notify(app, syscall.SIGUSR1)
+	defer signal.Stop(app)
+	stop := CloseOnSignal(time.Second, syscall.SIGUSR1)
+	defer stop()
+
+	raise := func() {
+		t.Helper()
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-app:
+		case <-time.After(time.Second):
+			t.Fatal("the program's handler missed the signal")
+		}
+	}
+	raise()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		configMutex.Lock()
+		closed := current.sinks[0].Writer == os.Stderr
+		configMutex.Unlock()
+		if closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Close did not run")
+		}
+	}
+	// the program keeps its handler after the helper is done
+	raise()
+}
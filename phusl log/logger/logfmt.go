@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"strconv"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// logfmt Format (shared with the zap bench/logger)
+// -------------------------------------------------------------
+
+// logfmt keys of the FormatLogfmt sinks. The zap bench/logger writes the
+// same line:
+//
+//	ts=2026-10-17T05:22:23.348Z level=info component=PFCP msg="Packet processed" seid=42
+//
+// "caller" follows the component with WithCaller; "stack" comes last when
+// present.
+const (
+	LogfmtTimeKey      = "ts"
+	LogfmtLevelKey     = "level"
+	LogfmtComponentKey = "component"
+	LogfmtCallerKey    = "caller"
+	LogfmtMessageKey   = "msg"
+	LogfmtStackKey     = "stack"
+)
+
+// logfmtFormatter renders entries in the shared logfmt format.
+type logfmtFormatter struct {
+	time *timeFormat // RFC3339 with milliseconds, in UTC with WithUTC
+}
+
+func newLogfmtFormatter(o options) *logfmtFormatter {
+	return &logfmtFormatter{time: newTimeFormat(TimeFormatRFC3339, o.utc)}
+}
+
+// appendLine appends the logfmt rendering of args to b.
+func (f *logfmtFormatter) appendLine(b []byte, args *log.FormatterArgs) []byte {
+	b = append(b, LogfmtTimeKey+"="...)
+	if f.time != nil {
+		b = f.time.appendTime(b, args.Time)
+	} else {
+		b = append(b, args.Time...)
+	}
+	b = append(b, " "+LogfmtLevelKey+"="...)
+	b = appendLogfmtString(b, args.Level)
+	if component := args.Get("component"); component != "" {
+		b = append(b, " "+LogfmtComponentKey+"="...)
+		b = appendLogfmtString(b, component)
+	}
+	if args.Caller != "" {
+		b = append(b, " "+LogfmtCallerKey+"="...)
+		b = appendLogfmtString(b, args.Caller)
+	}
+	b = append(b, " "+LogfmtMessageKey+"="...)
+	b = appendLogfmtString(b, args.Message)
+	for _, kv := range args.KeyValues {
+		if kv.Key == "component" {
+			continue
+		}
+		b = append(b, ' ')
+		b = appendLogfmtKey(b, kv.Key)
+		b = append(b, '=')
+		switch kv.ValueType {
+		case 's', 'o':
+			b = appendLogfmtString(b, kv.Value) // objects and arrays as their JSON text
+		default:
+			b = append(b, kv.Value...) // numbers, booleans and null
+		}
+	}
+	if args.Stack != "" {
+		b = append(b, " "+LogfmtStackKey+"="...)
+		b = appendLogfmtString(b, args.Stack)
+	}
+	return append(b, '\n')
+}
+
+// appendLogfmtString appends s, quoted and escaped only if it needs it.
+func appendLogfmtString(b []byte, s string) []byte {
+	if needsQuote(s) {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, s...)
+}
+
+// appendLogfmtKey appends key with the bytes logfmt does not allow in keys
+// (space, '=', '"' and control bytes) replaced by '_'.
+func appendLogfmtKey(b []byte, key string) []byte {
+	if key == "" {
+		return append(b, '_')
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			b = append(b, '_')
+		} else {
+			b = append(b, c)
+		}
+	}
+	return b
+}
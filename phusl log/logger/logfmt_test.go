@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	args := &log.FormatterArgs{
+		Time:    "2026-10-17T05:22:23.348Z",
+		Level:   "info",
+		Caller:  "smf/pfcp.go:42",
+		Message: "Packet processed",
+	}
+	for _, kv := range [][3]string{
+		{"component", "PFCP", "s"},
+		{"seid", "42", "n"},
+		{"ok", "true", "b"},
+		{"ue", `imsi "001"`, "s"},
+		{"empty", "", "s"},
+		{"bad key=", "x", "s"},
+		{"ids", `[1,"a b"]`, "o"},
+	} {
+		args.KeyValues = append(args.KeyValues, struct {
+			Key, Value string
+			ValueType  byte
+		}{kv[0], kv[1], kv[2][0]})
+	}
+
+	got := string(newLogfmtFormatter(options{}).appendLine(nil, args))
+	want := `ts=2026-10-17T05:22:23.348Z level=info component=PFCP caller=smf/pfcp.go:42 msg="Packet processed" seid=42 ok=true ue="imsi \"001\"" empty="" bad_key_=x ids="[1,\"a b\"]"` + "\n"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+// BenchmarkSinkFormats compares the console, JSON and logfmt encodings of
+// the same entry.
+func BenchmarkSinkFormats(b *testing.B) {
+	for _, format := range []string{FormatConsole, FormatJSON, FormatLogfmt} {
+		b.Run(format, func(b *testing.B) {
+			var out discardCounter
+			l := log.Logger{Level: log.InfoLevel, Writer: newSinkWriter(buildOptions([]Option{
+				WithFormat(format), WithColor(ColorNever), WithSinks(Sink{Writer: &out, Level: log.InfoLevel}),
+			}))}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Info().Str("component", "PFCP").Int("UE_ID", 1001).Msg("Packet processed successfully")
+			}
+		})
+	}
+}
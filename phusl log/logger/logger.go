@@ -1,228 +1,273 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
-	"io"
-	"strings"
+	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/phuslu/log"
 )
 
 // -------------------------------------------------------------
-// 1) ANSI Color Codes & Minimal Level-To-Color Logic
+// 1) ANSI Color Codes (level labels and colors live in theme.go)
 // -------------------------------------------------------------
 var (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorCyan   = "\033[36m"
-	colorWhite  = "\033[97m"
+	colorReset   = "\033[0m"
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorMagenta = "\033[35m"
+	colorCyan    = "\033[36m"
+	colorWhite   = "\033[97m"
 )
 
-// levelToColor maps a phuslu/log level to an ANSI color + short label
-func levelToColor(level string) (colorCode, label string) {
-	switch strings.ToLower(level) {
-	case "debug":
-		return colorCyan, "DEBUG"
-	case "info":
-		return colorGreen, "INFO "
-	case "warn":
-		return colorYellow, "WARN "
-	case "error":
-		return colorRed, "ERR  "
-	case "fatal":
-		// White text on a red background
-		return "\033[41m\033[37m", "FATAL"
-	default:
-		return colorWhite, strings.ToUpper(level)
-	}
-}
-
 // -------------------------------------------------------------
-// 2) Custom Console Formatter (No extra time parsing or fields)
-// -------------------------------------------------------------
-func customConsoleFormatter(w io.Writer, args *log.FormatterArgs) (int, error) {
-	// phuslu/log sets args.Time to an RFC3339Nano string by default (e.g. "2025-03-08T12:34:56.789Z").
-	// We skip re-parsing for performance; we’ll just print it as-is.
-
-	colorCode, levelLabel := levelToColor(args.Level)
-
-	// Format: "timestamp | colored-level | message"
-	// Example:
-	// 2025-03-08T12:34:56.789Z | [GREEN]INFO  | Hello World
-	return fmt.Fprintf(w, "%s | \033[0m%s%s\033[0m | %s\n",
-		args.Time,    // e.g. "2025-03-08T12:34:56.789Z"
-		colorCode,    // e.g. "\033[32m"
-		levelLabel,   // e.g. "INFO "
-		args.Message, // e.g. "Hello World"
-	)
-}
-
-// -------------------------------------------------------------
-// 3) Global Logger + Initialization
+// 2) Global Logger + Initialization
 // -------------------------------------------------------------
 var (
+	// globalLogger backs the component loggers and lopu backs Lopu. Both
+	// write through output, so Reconfigure reaches every holder of either.
 	globalLogger log.Logger
-	initOnce     sync.Once
+	lopu         log.Logger
+	output       swapWriter
+
+	// configMutex serializes Initialize and Reconfigure; configured is set
+	// once the first call has built the loggers.
+	configMutex sync.Mutex
+	configured  bool
+
+	// current holds the options of the last configure, for Close.
+	current options
+
+	// callerDepth is the log.Logger.Caller value for entries started directly
+	// on Lopu (0 disables caller output). Component loggers add one frame.
+	callerDepth int
 )
 
-// Initialize sets up the global logger just once.
-func Initialize(level log.Level) {
-	initOnce.Do(func() {
-		consoleWriter := &log.ConsoleWriter{
-			ColorOutput: false, // We'll manually colorize in customConsoleFormatter
-			Formatter:   customConsoleFormatter,
-		}
+// Initialize sets up the global logger and the component loggers. level may
+// be a built-in level or one returned by RegisterLevel, such as NoticeLevel.
+// The LOG_* environment variables (see EnvLevel) override level and opts.
+// Later calls reconfigure the running logger like Reconfigure, except that
+// the caller options keep their first value. It panics on an invalid option,
+// such as a malformed WithComponentLevels spec. Invalid LOG_* variables, and
+// LOG_OUTPUT files that cannot be opened, are reported on stderr and ignored.
+func Initialize(level log.Level, opts ...Option) {
+	level, envOpts, files, err := envOptions(level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\nlogger: ignoring these LOG_* settings\n", err)
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	o := buildOptions(append(opts[:len(opts):len(opts)], envOpts...))
+	if o.err != nil {
+		closeFiles(files)
+		panic(o.err)
+	}
+	configure(level, o)
+	if files != nil {
+		replaceConfigFiles(files)
+	}
+}
 
+// configure applies o, taking the caller options only on the first call; the
+// caller must hold configMutex.
+func configure(level log.Level, o options) {
+	applyOutputs(o)
+	current = o
+	if !configured {
+		// globalLogger never records the caller itself: that is done per entry
+		// point (Lopu and ComponentLogger) so the wrapper frames are skipped.
 		globalLogger = log.Logger{
-			Level:  level,         // e.g. log.InfoLevel, log.DebugLevel, etc.
-			Writer: consoleWriter, // Output to stdout in our custom format
-			// Caller: 1,           // If you ever want file:line info in args.Caller
+			Level:  builtinThreshold(level), // e.g. log.InfoLevel, log.DebugLevel, etc.
+			Writer: &output,                 // Output to stdout in our custom format
 		}
-	})
+		if o.caller {
+			callerDepth = 1 + o.callerSkip
+		}
+		lopu = globalLogger
+		lopu.Caller = callerDepth
+
+		initComponentLoggers()
+		configured = true
+	}
+	setLevels(level, o.componentLevels)
+	if o.rateLimits != nil {
+		setRateLimits(o.rateLimits)
+	}
 }
 
-// Logger returns the global logger.
-func Logger() log.Logger {
-	return globalLogger
+// applyOutputs replaces the output with that of o: its sinks, redacting if
+// set, behind sampling and dedup. The caller must hold configMutex.
+func applyOutputs(o options) {
+	w := newSinkWriter(o)
+	direct := w
+	if o.sampling != nil {
+		w = newSamplingWriter(*o.sampling, w)
+	}
+	old := activeDeduper
+	activeDeduper = nil
+	if o.dedup > 0 {
+		activeDeduper = newDeduper(w, o.dedup)
+		w = activeDeduper
+	}
+	output.swap(w, direct)
+	if old != nil {
+		old.flush() // pending repeats go to the outputs they were logged to
+	}
 }
 
-var (
-	Lopu log.Logger
-)
+// Logger returns the global logger, with caller output configured. It is the
+// same logger as Lopu.
+func Logger() *log.Logger {
+	return Lopu
+}
 
+// Lopu is the global logger without a component column. It is a pointer so
+// that Reconfigure and SetLevel apply to everyone holding it.
+var Lopu = &lopu
+
+// Ready initializes the logger at info level, or LOG_LEVEL, unless
+// Initialize already ran.
 func Ready() {
-	Initialize(log.InfoLevel)
+	configMutex.Lock()
+	done := configured
+	configMutex.Unlock()
+	if !done {
+		Initialize(log.InfoLevel)
+	}
+}
+
+// -------------------------------------------------------------
+// 3) Runtime Reconfiguration
+// -------------------------------------------------------------
 
-	// 2) Use the global logger
-	Lopu = Logger()
+// Reconfigure atomically swaps the level, output, formatter, theme and
+// timestamp settings of the running logger, as seen by Lopu and every
+// component logger. Per-component levels are kept unless WithComponentLevels
+// is given. It initializes the logger if needed. The caller options are fixed
+// by the first configuration; changing them is an error.
+func Reconfigure(level log.Level, opts ...Option) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	o := buildOptions(opts)
+	if o.err != nil {
+		return o.err
+	}
+	if configured {
+		depth := 0
+		if o.caller {
+			depth = 1 + o.callerSkip
+		}
+		if depth != callerDepth {
+			return errors.New("logger: caller options cannot change after Initialize")
+		}
+	}
+	configure(level, o)
+	return nil
+}
+
+// callerOnReload returns the caller options for a reload that asks for
+// caller output to be on or off. Once the logger is configured they keep the
+// running settings, and ignored reports whether want had to be dropped.
+func callerOnReload(want bool) (opts []Option, ignored bool) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	on, skip := want, 0
+	if configured {
+		on, skip = callerDepth != 0, callerDepth-1
+	}
+	if on {
+		opts = []Option{WithCaller(), WithCallerSkip(skip)}
+	}
+	return opts, on != want
+}
+
+// SetLevel changes the level of Lopu and of every component logger without
+// a per-component level.
+func SetLevel(level log.Level) {
+	setLevels(level, nil)
+}
+
+// GetLevel returns the level set by Initialize, Reconfigure or SetLevel.
+func GetLevel() log.Level {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	return defaultLevel
+}
+
+// swapWriter forwards entries to the current writer, which Reconfigure
+// replaces without locking the write path.
+type swapWriter struct {
+	current atomic.Pointer[writerBox]
+}
+
+// writerBox lets atomic.Pointer hold an interface value. active counts the
+// entries being written to it, so a replaced writer can be drained before
+// its files are closed.
+type writerBox struct {
+	log.Writer
+	direct log.Writer // the sinks of Writer without sampling and dedup
+	active atomic.Int64
+}
+
+// WriteEntry implements log.Writer. A fatal entry is followed by a flush of
+// every sink, since Entry.Msg exits as soon as this returns.
+func (s *swapWriter) WriteEntry(e *log.Entry) (n int, err error) {
+	if b := s.acquire(); b != nil {
+		n, err = b.WriteEntry(e)
+		b.active.Add(-1)
+	} else {
+		n = len(e.Value())
+	}
+	if e.Level == log.FatalLevel {
+		flushForExit()
+	}
+	return n, err
+}
+
+// directWriter writes entries to the current sinks, bypassing sampling and
+// dedup, for reports that must not be lost.
+type directWriter struct{}
+
+// WriteEntry implements log.Writer.
+func (directWriter) WriteEntry(e *log.Entry) (int, error) {
+	b := output.acquire()
+	if b == nil {
+		return len(e.Value()), nil
+	}
+	defer b.active.Add(-1)
+	return b.direct.WriteEntry(e)
+}
+
+// acquire returns the current writer counted as active, or nil before the
+// first configure. The second load makes sure that swap either waits for
+// the entry or the entry goes to the new writer.
+func (s *swapWriter) acquire() *writerBox {
+	for {
+		b := s.current.Load()
+		if b == nil {
+			return nil
+		}
+		b.active.Add(1)
+		if s.current.Load() == b {
+			return b
+		}
+		b.active.Add(-1)
+	}
+}
+
+// swap installs w, writing to the sinks of direct, as the current writer and
+// waits for the entries being written to the previous one, for at most
+// drainTimeout, so its outputs can be closed.
+func (s *swapWriter) swap(w, direct log.Writer) {
+	old := s.current.Swap(&writerBox{Writer: w, direct: direct})
+	if old != nil {
+		drain(&old.active)
+	}
 }
-// ---------------------------------------------------------------------------------
-// color codes for levels
-// var (
-// 	colorReset   = "\033[0m"
-// 	colorRed     = "\033[31m"
-// 	colorGreen   = "\033[32m"
-// 	colorYellow  = "\033[33m"
-// 	colorBlue    = "\033[34m"
-// 	colorCyan    = "\033[36m"
-// 	colorWhite   = "\033[97m"
-// 	colorMagenta = "\033[35m"
-// )
-
-// // create a global logger + a custom console writer
-// var (
-// 	globalLogger log.Logger
-// 	initOnce     sync.Once
-// )
-
-// // SubLogger wraps a phuslu/log Logger and automatically adds "component" to each entry.
-// type SubLogger struct {
-// 	baseLogger *log.Logger
-// 	component  string
-// }
-
-// // Info() returns a new log.Entry with the component field set.
-// func (s *SubLogger) Info() *log.Entry {
-// 	return s.baseLogger.Info().Str("component", s.component)
-// }
-
-// func (s *SubLogger) Warn() *log.Entry {
-// 	return s.baseLogger.Warn().Str("component", s.component)
-// }
-
-// func (s *SubLogger) Error() *log.Entry {
-// 	return s.baseLogger.Error().Str("component", s.component)
-// }
-
-// func (s *SubLogger) Debug() *log.Entry {
-// 	return s.baseLogger.Debug().Str("component", s.component)
-// }
-
-// func (s *SubLogger) Fatal() *log.Entry {
-// 	return s.baseLogger.Fatal().Str("component", s.component)
-// }
-
-// // levelToColor maps a phuslu/log level to an ANSI color + short label (mimicking your Zap scheme).
-// func levelToColor(level string) (colorCode, levelStr string) {
-// 	switch strings.ToLower(level) {
-// 	case "debug":
-// 		return colorCyan, "DEBUG"
-// 	case "info":
-// 		return colorGreen, "INFO "
-// 	case "warn":
-// 		return colorYellow, "WARN "
-// 	case "error":
-// 		return colorRed, "ERR  "
-// 	case "fatal":
-// 		// white text on red background
-// 		return "\033[41m\033[37m", "FATAL"
-// 	default:
-// 		return colorWhite, strings.ToUpper(level)
-// 	}
-// }
-
-// // customConsoleFormatter replicates the line format:
-// // timestamp | colored-level | caller | component | message
-// func customConsoleFormatter(w io.Writer, args *log.FormatterArgs) (int, error) {
-// 	// Reformat the timestamp from RFC3339Nano to "2006-01-02 | 15:04:05.000"
-// 	parsed, err := time.Parse(time.RFC3339Nano, args.Time)
-// 	if err == nil {
-// 		args.Time = parsed.Format("2006-01-02 | 15:04:05.000")
-// 	}
-
-// 	// colorize the level
-// 	colorCode, levelStr := levelToColor(args.Level)
-
-// 	// Extract "component" from KeyValues
-// 	var component string
-// 	for _, kv := range args.KeyValues {
-// 		if kv.Key == "component" {
-// 			component = kv.Value
-// 			break
-// 		}
-// 	}
-
-// 	// If you want to display file:line, phuslu/log must be configured with logger.Caller=1 or so.
-// 	// That would populate args.Caller. If not set, args.Caller might be empty.
-// 	return fmt.Fprintf(w,
-// 		"%s | \033[0m%s%s\033[0m | %s | %-5s | %s\n",
-// 		args.Time,           // e.g. "2025-03-08 | 12:34:56.789"
-// 		colorCode, levelStr, // e.g. "[green]INFO "
-// 		args.Caller,  // e.g. "main.go:42"
-// 		component,    // e.g. "MAIN"
-// 		args.Message, // e.g. "Initialization complete"
-// 	)
-// }
-
-// // Initialize sets up the global logger with your console format, only once.
-// func Initialize(level log.Level) {
-// 	initOnce.Do(func() {
-// 		consoleWriter := &log.ConsoleWriter{
-// 			ColorOutput: false, // We'll handle color in customConsoleFormatter
-// 			Formatter:   customConsoleFormatter,
-// 		}
-
-// 		// Build the global logger
-// 		globalLogger = log.Logger{
-// 			Level:  level, // e.g. log.InfoLevel
-// 			Writer: consoleWriter,
-// 			// Caller:  1,  // uncomment if you want file:line in args.Caller
-// 		}
-// 	})
-// }
-
-// // Logger returns the *global* logger (in case you need it).
-// func Logger() log.Logger {
-// 	return globalLogger
-// }
-
-// // NewComponentLogger returns a SubLogger that automatically attaches "component" to each log entry.
-// func NewComponentLogger(name string) *SubLogger {
-// 	return &SubLogger{
-// 		baseLogger: &globalLogger,
-// 		component:  name,
-// 	}
-// }
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestReconfigure(t *testing.T) {
+	var first, second bytes.Buffer
+	if err := Reconfigure(log.InfoLevel, WithWriter(&first), WithColor(ColorNever)); err != nil {
+		t.Fatal(err)
+	}
+	// holders taken before any later change
+	l, pfcp := Lopu, NewComponentLogger("PFCP")
+
+	l.Info().Msg("one")
+	pfcp.Debug().Msg("filtered")
+	SetLevel(log.WarnLevel)
+	pfcp.Info().Msg("filtered")
+	pfcp.Warn().Msg("two")
+	if got := first.String(); strings.Count(got, "\n") != 2 || !strings.Contains(got, "INFO  | one\n") || !strings.Contains(got, "WARN  | PFCP  | two\n") {
+		t.Errorf("first writer:\n%s", got)
+	}
+
+	if err := Reconfigure(NoticeLevel, WithWriter(&second), WithColor(ColorAlways)); err != nil {
+		t.Fatal(err)
+	}
+	if GetLevel() != NoticeLevel {
+		t.Errorf("GetLevel() = %v, want notice", GetLevel())
+	}
+	l.Info().Msg("filtered")
+	pfcp.Notice().Msg("three")
+	if got := second.String(); got == "" || strings.Contains(got, "filtered") || !strings.Contains(got, "\033[34mNOTE \033[0m | PFCP  | three\n") {
+		t.Errorf("second writer:\n%q", got)
+	}
+	if strings.Contains(first.String(), "three") {
+		t.Errorf("old writer still in use")
+	}
+
+	if err := Reconfigure(log.InfoLevel, WithWriter(&second), WithCaller()); err == nil {
+		t.Errorf("changing the caller options should fail")
+	}
+}
+
+func TestReconfigureConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	w := lockedWriter{&mu, &buf}
+	if err := Reconfigure(log.InfoLevel, WithWriter(w)); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				Lopu.Info().Int("j", j).Msg("packet")
+				PfcpLog.Warn().Msg("packet")
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		_ = Reconfigure(log.InfoLevel, WithWriter(w), WithTimeFormat(TimeFormatRFC3339))
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if !strings.HasSuffix(line, "packet") && !strings.Contains(line, "packet j=") {
+			t.Fatalf("torn line %q", line)
+		}
+	}
+}
+
+func TestReconfigureDrains(t *testing.T) {
+	w := &gateWriter{entered: make(chan struct{}), release: make(chan struct{})}
+	if err := Reconfigure(log.InfoLevel, WithWriter(w)); err != nil {
+		t.Fatal(err)
+	}
+	go Lopu.Info().Msg("in flight")
+	<-w.entered
+
+	done := make(chan struct{})
+	go func() {
+		_ = Reconfigure(log.InfoLevel, WithWriter(io.Discard))
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Reconfigure returned while an entry was being written to the old writer")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(w.release)
+	<-done
+}
+
+// gateWriter blocks its first write until release is closed.
+type gateWriter struct {
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	g.once.Do(func() {
+		close(g.entered)
+		<-g.release
+	})
+	return len(p), nil
+}
+
+// lockedWriter serializes writes from concurrent goroutines.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (l lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// -------------------------------------------------------------
+// Initialize Options
+// -------------------------------------------------------------
+
+// Option customizes Initialize.
+type Option func(*options)
+
+type options struct {
+	caller     bool // add the short file:line of the call site
+	callerSkip int  // extra frames to skip for user-side logging helpers
+
+	timeFormat string // TimeFormat* constant or time.Format layout
+	utc        bool   // render timestamps in UTC instead of local time
+
+	sinks  []Sink // outputs, a console on os.Stdout by default
+	format string // format of sinks without one, FormatConsole by default
+
+	color ColorMode // ANSI colors, ColorAuto by default
+	theme *Theme    // level labels and colors, DefaultTheme if nil
+
+	componentLevels *LevelSpec     // per-component levels, unchanged if nil
+	rateLimits      *RateLimitSpec // per-component rate limits, unchanged if nil
+
+	sampling *Sampling     // entry sampling, off if nil
+	dedup    time.Duration // longest hold of repeated entries, no dedup if zero
+	redact   *redactor     // subscriber identity redaction, off if nil
+
+	panicPolicy PanicPolicy // what RecoverAndLog does after logging
+
+	err error // first invalid option
+}
+
+// WithCaller adds the short file:line of the real call site to every entry,
+// shown as its own column by customConsoleFormatter.
+func WithCaller() Option {
+	return func(o *options) {
+		o.caller = true
+	}
+}
+
+// WithCallerSkip is like WithCaller, but additionally skips skip stack frames
+// for code that logs through its own helper functions.
+func WithCallerSkip(skip int) Option {
+	return func(o *options) {
+		o.caller = true
+		o.callerSkip += skip
+	}
+}
+
+// WithTimeFormat sets the timestamp layout: one of the TimeFormat* constants
+// or any time.Format layout, e.g. TimeFormatZap for "2006-01-02 | 15:04:05.000".
+func WithTimeFormat(format string) Option {
+	return func(o *options) {
+		o.timeFormat = format
+	}
+}
+
+// WithUTC renders timestamps in UTC instead of local time.
+func WithUTC() Option {
+	return func(o *options) {
+		o.utc = true
+	}
+}
+
+// WithWriter sends the console output to w instead of os.Stdout.
+func WithWriter(w io.Writer) Option {
+	return WithWriters(w)
+}
+
+// WithWriters sends the console output to each of ws, with colors decided
+// per writer.
+func WithWriters(ws ...io.Writer) Option {
+	sinks := make([]Sink, len(ws))
+	for i, w := range ws {
+		sinks[i] = Sink{Writer: w}
+	}
+	return WithSinks(sinks...)
+}
+
+// WithSinks sends every entry to each sink at or above the sink's level,
+// formatted once per distinct format.
+func WithSinks(sinks ...Sink) Option {
+	return func(o *options) {
+		o.sinks = sinks
+	}
+}
+
+// WithFormat sets the format of sinks that do not name one, including the
+// default stdout sink: FormatConsole, FormatJSON or FormatLogfmt.
+func WithFormat(format string) Option {
+	return func(o *options) {
+		if !sinkFormats[format] {
+			if o.err == nil {
+				o.err = fmt.Errorf("logger: unknown format %q", format)
+			}
+			return
+		}
+		o.format = format
+	}
+}
+
+// overrideFormat sets the format of every sink, for LOG_FORMAT, which
+// overrides the formats set in code as it does those of a config file.
+func overrideFormat(format string) Option {
+	return func(o *options) {
+		o.format = format
+		if len(o.sinks) == 0 {
+			return
+		}
+		sinks := make([]Sink, len(o.sinks))
+		for i, s := range o.sinks {
+			s.Format = format
+			sinks[i] = s
+		}
+		o.sinks = sinks
+	}
+}
+
+// WithColor overrides terminal detection for console colors.
+func WithColor(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// WithTheme sets the console level labels and colors, e.g. HighContrastTheme().
+func WithTheme(t *Theme) Option {
+	return func(o *options) {
+		o.theme = t
+	}
+}
+
+// WithComponentLevels sets per-component levels from a spec such as
+// "PFCP=debug,SESS=debug,*=info"; see ParseLevelSpec.
+func WithComponentLevels(spec string) Option {
+	return func(o *options) {
+		s, err := ParseLevelSpec(spec)
+		if err != nil {
+			if o.err == nil {
+				o.err = err
+			}
+			return
+		}
+		o.componentLevels = s
+	}
+}
+
+// formatOf returns the format of a sink.
+func (o *options) formatOf(s Sink) string {
+	switch {
+	case s.Format != "":
+		return s.Format
+	case o.format != "":
+		return o.format
+	}
+	return FormatConsole
+}
+
+// colorFor returns the color mode of a console sink.
+func (o *options) colorFor(s Sink) ColorMode {
+	if s.Color != ColorAuto {
+		return s.Color
+	}
+	return o.color
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Panic Recovery and Fatal Exit
+// -------------------------------------------------------------
+
+// PanicPolicy is what RecoverAndLog does once a panic is logged and the
+// sinks are flushed.
+type PanicPolicy int
+
+const (
+	// PanicRepanic panics again with the same value, so the runtime or an
+	// outer recover still sees it. It is the default.
+	PanicRepanic PanicPolicy = iota
+	// PanicExit exits with status 2, as the runtime does for a panic.
+	PanicExit
+)
+
+// panicExitCode is the status of PanicExit.
+const panicExitCode = 2
+
+// ExitFlushTimeout bounds the flush that runs before a fatal entry or
+// RecoverAndLog ends the process, so a stuck sink cannot keep it alive.
+var ExitFlushTimeout = 5 * time.Second
+
+// WithPanicPolicy sets what RecoverAndLog does after logging; see
+// PanicPolicy.
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(o *options) {
+		if p != PanicRepanic && p != PanicExit {
+			o.err = fmt.Errorf("logger: unknown panic policy %d", p)
+			return
+		}
+		o.panicPolicy = p
+	}
+}
+
+// RecoverAndLog, deferred at the top of a goroutine, logs a panic at fatal
+// level with the component's tag, the panic value and the full goroutine
+// stack, flushes every sink, and then re-panics or exits according to the
+// panic policy:
+//
+//	go func() {
+//		defer logger.RecoverAndLog(logger.PfcpLog)
+//		...
+//	}()
+//
+// It must be deferred directly; it does nothing if there is no panic.
+func RecoverAndLog(c *ComponentLogger) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	logPanic(c, v, debug.Stack()) // flushes every sink
+
+	configMutex.Lock()
+	policy := current.panicPolicy
+	configMutex.Unlock()
+	if policy == PanicExit {
+		os.Exit(panicExitCode)
+	}
+	panic(v)
+}
+
+// logPanic writes the entry of RecoverAndLog. It goes through Logger.Log
+// with a "fatal" level field, like a registered level, because Msg would
+// exit at log.FatalLevel; panicWriter makes it a fatal entry for the
+// writers below.
+func logPanic(c *ComponentLogger, v any, stack []byte) {
+	l := globalLogger
+	l.Writer = panicWriter{}
+	e := l.Log().Str("level", log.FatalLevel.String())
+	if c != nil {
+		e = e.Context(c.context)
+	}
+	if err, ok := v.(error); ok {
+		e = e.Context(Err(err))
+	}
+	e.Bytes(JSONStackKey, stack).Msg(fmt.Sprint("panic: ", v))
+}
+
+// panicWriter writes the entry of logPanic to the output at log.FatalLevel,
+// so sink levels take it and sampling and dedup never hold it back, and
+// flushes every sink after it. The level is restored before Entry.Msg
+// looks at it.
+type panicWriter struct{}
+
+// WriteEntry implements log.Writer.
+func (panicWriter) WriteEntry(e *log.Entry) (int, error) {
+	level := e.Level
+	e.Level = log.FatalLevel
+	defer func() { e.Level = level }()
+	return output.WriteEntry(e)
+}
+
+// flushForExit runs Flush within ExitFlushTimeout before the process ends,
+// reporting what could not be written on stderr.
+func flushForExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), ExitFlushTimeout)
+	defer cancel()
+	if err := Flush(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	var out syncBuffer
+	if err := Reconfigure(log.InfoLevel, WithSinks(Sink{Writer: &AsyncWriter{Writer: &out}, Color: ColorNever})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+
+	errBoom := errors.New("boom")
+	repanicked := make(chan any)
+	go func() {
+		defer func() { repanicked <- recover() }()
+		defer RecoverAndLog(NewComponentLogger("TASK"))
+		panic(errBoom)
+	}()
+	if v := <-repanicked; v != errBoom {
+		t.Fatalf("re-panicked with %v, want the original value", v)
+	}
+
+	// the queue was flushed before the re-panic, without any Close
+	got := out.String()
+	lines := strings.Split(got, "\n")
+	if !strings.Contains(lines[0], "FATAL") || !strings.HasSuffix(lines[0], `| TASK  | panic: boom error=boom`) {
+		t.Errorf("entry line = %q", lines[0])
+	}
+	if !strings.Contains(got, "goroutine ") || !strings.Contains(got, "logger.TestRecoverAndLog.func") {
+		t.Errorf("goroutine stack missing:\n%s", got)
+	}
+
+	// no panic, nothing logged
+	func() { defer RecoverAndLog(NewComponentLogger("TASK")) }()
+	if out.String() != got {
+		t.Error("RecoverAndLog logged without a panic")
+	}
+}
+
+func TestWithPanicPolicy(t *testing.T) {
+	if o := buildOptions([]Option{WithPanicPolicy(PanicExit)}); o.err != nil || o.panicPolicy != PanicExit {
+		t.Errorf("policy %d, err %v", o.panicPolicy, o.err)
+	}
+	if o := buildOptions([]Option{WithPanicPolicy(7)}); o.err == nil {
+		t.Error("unknown policy accepted")
+	}
+}
@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Redaction of Subscriber Identifiers
+// -------------------------------------------------------------
+
+// Redaction modes.
+const (
+	RedactMask     = "mask"     // replace the characters after Keep with '*'
+	RedactTruncate = "truncate" // drop the characters after Keep
+	RedactHash     = "hash"     // replace the identifier with a keyed hash, equal for equal identifiers
+)
+
+// DefaultRedactFields are the field names redacted when Redaction.Fields is
+// nil.
+var DefaultRedactFields = []string{"supi", "imsi", "msisdn", "gpsi", "imei", "imeisv", "pei"}
+
+// DefaultRedactPatterns find identifiers in messages and string fields when
+// Redaction.Patterns is nil: typed SUPIs, GPSIs and PEIs ("imsi-208930000000003",
+// "msisdn-491701234567", "imeisv-3569380356438091"), bare 15 and 16 digit
+// IMSIs and IMEIs, and E.164 MSISDNs with a '+'.
+var DefaultRedactPatterns = []string{
+	`(?i)\b(?:imsi|supi|msisdn|gpsi|imei|imeisv|pei)-[0-9]{5,16}\b`,
+	`\b[0-9]{15,16}\b`,
+	`\+[0-9]{8,15}\b`,
+}
+
+// redactMinDigits is the run of digits text needs before the patterns are
+// tried, which keeps redaction cheap for most entries.
+const redactMinDigits = 5
+
+// Redaction removes subscriber identities (SUPI/IMSI, GPSI/MSISDN,
+// PEI/IMEI) from entries before they reach any sink. Values of fields named
+// in Fields are redacted whole, whatever their type; Patterns are replaced
+// in the message, in other string fields and in error chains from Err.
+// Values in other nested objects and arrays are not inspected.
+//
+// An identifier keeps its type label, such as "imsi-" or '+', so
+// "imsi-208930000000003" becomes "imsi-20893**********" with RedactMask and
+// Keep 5 (the MCC and MNC), "imsi-20893" with RedactTruncate, and
+// "imsi-#9c1d..." with RedactHash, which lets traces of one subscriber be
+// correlated without revealing it.
+type Redaction struct {
+	Mode     string   // RedactMask (default), RedactTruncate or RedactHash
+	Fields   []string // field names matched case-insensitively, DefaultRedactFields if nil
+	Patterns []string // regular expressions with 5+ digits in a row, DefaultRedactPatterns if nil
+	Keep     int      // leading characters left in clear by mask and truncate
+	Key      []byte   // secret for RedactHash, required by it
+}
+
+// WithRedaction redacts subscriber identities; see Redaction.
+func WithRedaction(r Redaction) Option {
+	return func(o *options) {
+		rd, err := newRedactor(r)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.redact = rd
+	}
+}
+
+// redactor applies a Redaction.
+type redactor struct {
+	mode   string
+	fields map[string]bool // lower-case names
+	re     *regexp.Regexp  // all patterns, nil if none
+	keep   int
+	key    []byte
+}
+
+func newRedactor(r Redaction) (*redactor, error) {
+	switch r.Mode {
+	case "":
+		r.Mode = RedactMask
+	case RedactMask, RedactTruncate:
+	case RedactHash:
+		if len(r.Key) == 0 {
+			return nil, fmt.Errorf("logger: redaction: %s mode needs a key", RedactHash)
+		}
+	default:
+		return nil, fmt.Errorf("logger: redaction: unknown mode %q", r.Mode)
+	}
+	if r.Keep < 0 {
+		return nil, fmt.Errorf("logger: redaction: negative keep %d", r.Keep)
+	}
+	if r.Fields == nil {
+		r.Fields = DefaultRedactFields
+	}
+	if r.Patterns == nil {
+		r.Patterns = DefaultRedactPatterns
+	}
+	rd := &redactor{mode: r.Mode, fields: make(map[string]bool, len(r.Fields)), keep: r.Keep, key: r.Key}
+	for _, f := range r.Fields {
+		rd.fields[strings.ToLower(f)] = true
+	}
+	if len(r.Patterns) > 0 {
+		alts := make([]string, len(r.Patterns))
+		for i, p := range r.Patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return nil, fmt.Errorf("logger: redaction: pattern %q: %w", p, err)
+			}
+			alts[i] = "(?:" + p + ")"
+		}
+		rd.re = regexp.MustCompile(strings.Join(alts, "|"))
+	}
+	return rd, nil
+}
+
+// field reports whether the value of field name is redacted whole.
+func (r *redactor) field(name string) bool {
+	return r.fields[name] || r.fields[strings.ToLower(name)]
+}
+
+// text redacts the identifiers matched by the patterns in s.
+func (r *redactor) text(s string) string {
+	if r.re == nil || !hasDigitRun(s, redactMinDigits) {
+		return s
+	}
+	return r.re.ReplaceAllStringFunc(s, r.value)
+}
+
+// value redacts the identifier s, keeping its type label.
+func (r *redactor) value(s string) string {
+	label := identLabel(s)
+	id := s[len(label):]
+	switch r.mode {
+	case RedactHash:
+		m := hmac.New(sha256.New, r.key)
+		m.Write([]byte(id))
+		return label + "#" + hex.EncodeToString(m.Sum(nil)[:8])
+	case RedactTruncate:
+		return label + id[:min(r.keep, len(id))]
+	}
+	keep := min(r.keep, len(id))
+	return label + id[:keep] + strings.Repeat("*", len(id)-keep)
+}
+
+// identLabel returns the type label of an identifier: a leading '+' or
+// letters followed by '-', as in "imsi-" or "nai-".
+func identLabel(s string) string {
+	if strings.HasPrefix(s, "+") {
+		return "+"
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			if i > 0 && s[i] == '-' {
+				return s[:i+1]
+			}
+			break
+		}
+	}
+	return ""
+}
+
+// hasDigitRun reports whether s has n or more digits in a row.
+func hasDigitRun(s string, n int) bool {
+	run := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			if run++; run >= n {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+	return false
+}
+
+// redactArgs redacts a parsed entry before it is formatted.
+func (r *redactor) redactArgs(args *log.FormatterArgs) {
+	args.Message = r.text(args.Message)
+	for i := range args.KeyValues {
+		kv := &args.KeyValues[i]
+		switch {
+		case r.field(kv.Key):
+			kv.Value, kv.ValueType = r.value(kv.Value), 's'
+		case kv.ValueType == 's' || kv.Key == ErrorChainKey:
+			kv.Value = r.text(kv.Value) // a chain is an array of strings: replacements keep it valid
+		}
+	}
+}
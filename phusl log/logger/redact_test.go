@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestRedactorValue(t *testing.T) {
+	key := []byte("secret")
+	for _, tt := range []struct {
+		r    Redaction
+		in   string
+		want string
+	}{
+		{Redaction{}, "imsi-208930000000003", "imsi-***************"},
+		{Redaction{Keep: 5}, "imsi-208930000000003", "imsi-20893**********"},
+		{Redaction{Mode: RedactTruncate, Keep: 5}, "imsi-208930000000003", "imsi-20893"},
+		{Redaction{Keep: 2}, "+491701234567", "+49**********"},
+		{Redaction{Mode: RedactHash, Key: key}, "imsi-208930000000003", "imsi-#dc31ae9915e43d6f"},
+		{Redaction{Mode: RedactHash, Key: key}, "208930000000003", "#dc31ae9915e43d6f"},
+	} {
+		r, err := newRedactor(tt.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.value(tt.in); got != tt.want {
+			t.Errorf("%+v: value(%q) = %q, want %q", tt.r, tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []Redaction{{Mode: RedactHash}, {Mode: "scramble"}, {Keep: -1}, {Patterns: []string{"("}}} {
+		if _, err := newRedactor(bad); err == nil {
+			t.Errorf("newRedactor(%+v) accepted", bad)
+		}
+	}
+}
+
+func TestRedactorText(t *testing.T) {
+	r, _ := newRedactor(Redaction{Keep: 5})
+	for in, want := range map[string]string{
+		"Session established for imsi-208930000000003":                                "Session established for imsi-20893**********",
+		"UE 208930000000003 with PEI imeisv-3569380356438091 at MSISDN +491701234567": "UE 20893********** with PEI imeisv-35693*********** at MSISDN +49170*******",
+		"seid 42, teid 3405691582, 2026-10-17T05:57:02Z":                              "seid 42, teid 3405691582, 2026-10-17T05:57:02Z",
+	} {
+		if got := r.text(in); got != want {
+			t.Errorf("text(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	var console, js bytes.Buffer
+	err := Reconfigure(log.InfoLevel, WithRedaction(Redaction{Mode: RedactHash, Key: []byte("secret")}),
+		WithSinks(Sink{Writer: &console, Color: ColorNever}, Sink{Writer: &js, Format: FormatJSON}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+
+	NewComponentLogger("SESS").Info().Str("SUPI", "imsi-208930000000003").Int64("imei", 356938035643809).
+		Str("note", "gpsi msisdn-491701234567").Msg("PDU session for 208930000000003")
+
+	for _, out := range []string{console.String(), js.String()} {
+		for _, clear := range []string{"208930000000003", "356938035643809", "491701234567"} {
+			if strings.Contains(out, clear) {
+				t.Errorf("%s left in clear:\n%s", clear, out)
+			}
+		}
+	}
+	if !strings.Contains(console.String(), "PDU session for #dc31ae9915e43d6f SUPI=imsi-#dc31ae9915e43d6f") {
+		t.Errorf("hash does not correlate:\n%s", console.String())
+	}
+	if !strings.Contains(js.String(), `"imei":"#`) {
+		t.Errorf("numeric field not redacted to a string:\n%s", js.String())
+	}
+}
+
+// BenchmarkRedaction logs an entry carrying a SUPI field and an IMSI in the
+// message to a console sink, without redaction and in each mode.
+func BenchmarkRedaction(b *testing.B) {
+	b.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"off", nil},
+		{"mask", []Option{WithRedaction(Redaction{Keep: 5})}},
+		{"hash", []Option{WithRedaction(Redaction{Mode: RedactHash, Key: []byte("secret")})}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			if err := Reconfigure(log.InfoLevel, append(bm.opts, WithWriter(io.Discard), WithColor(ColorNever))...); err != nil {
+				b.Fatal(err)
+			}
+			sess := NewComponentLogger("SESS")
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sess.Info().Str("supi", "imsi-208930000000003").Int("pdu_session_id", 5).Msg("PDU session established for imsi-208930000000003")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"bytes"
+	"sync/atomic"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Sampling
+// -------------------------------------------------------------
+
+// Sampling thins out repeated entries. Within each Tick, the first First
+// entries with the same level and message are logged, then every
+// Thereafter-th. With Key set, entries are counted separately per value of
+// that field, e.g. "UE_ID", so one busy UE does not silence the others.
+// Fatal and panic entries are never sampled.
+//
+// Counters live in a fixed table indexed by a hash of the key, as in zap's
+// sampler, so memory stays bounded; rare collisions share a counter.
+type Sampling struct {
+	Tick       time.Duration // counting period, 1s if zero
+	First      int           // entries logged per key and tick before thinning
+	Thereafter int           // then log every Thereafter-th; 0 drops the rest
+	Key        string        // optional field name that splits the counters
+}
+
+// SamplingStats counts the entries seen by the sampler.
+type SamplingStats struct {
+	Sampled uint64 // logged
+	Dropped uint64 // discarded by the sampler
+}
+
+// samplingCounts are cumulative across Reconfigure.
+var samplingCounts struct {
+	sampled, dropped atomic.Uint64
+}
+
+// GetSamplingStats returns how many entries the sampler logged and dropped
+// since the program started.
+func GetSamplingStats() SamplingStats {
+	return SamplingStats{Sampled: samplingCounts.sampled.Load(), Dropped: samplingCounts.dropped.Load()}
+}
+
+// WithSampling enables sampling; see Sampling.
+func WithSampling(s Sampling) Option {
+	return func(o *options) {
+		o.sampling = &s
+	}
+}
+
+// sampleBuckets is the size of the counter table.
+const sampleBuckets = 8192
+
+// sampleCounter counts the entries of one key in the current tick.
+type sampleCounter struct {
+	resetAt atomic.Int64 // unix nanoseconds ending the tick
+	n       atomic.Uint64
+}
+
+// sampler is the counting shared by both backends.
+type sampler struct {
+	tick       int64
+	first      uint64
+	thereafter uint64
+	counters   [sampleBuckets]sampleCounter
+}
+
+func newSampler(s Sampling) *sampler {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &sampler{tick: int64(tick), first: uint64(max(s.First, 0)), thereafter: uint64(max(s.Thereafter, 0))}
+}
+
+// keep counts an entry with key hash h at now and reports whether it is
+// logged.
+func (s *sampler) keep(h uint64, now int64) bool {
+	n := s.counters[h%sampleBuckets].inc(now, s.tick)
+	if n <= s.first || s.thereafter != 0 && (n-s.first)%s.thereafter == 0 {
+		samplingCounts.sampled.Add(1)
+		return true
+	}
+	samplingCounts.dropped.Add(1)
+	return false
+}
+
+// inc counts one entry, starting a new tick if the last one has ended.
+func (c *sampleCounter) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if now < resetAt {
+		return c.n.Add(1)
+	}
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		return c.n.Add(1) // another goroutine started the tick
+	}
+	return 1
+}
+
+// FNV-1a, to hash sample keys without allocating.
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func fnvAdd[T string | []byte](h uint64, s T) uint64 {
+	for i := 0; i < len(s); i++ {
+		h = (h ^ uint64(s[i])) * fnvPrime
+	}
+	return h
+}
+
+// sampleHash hashes the level and message of an entry; the key field value
+// is added with fnvAdd.
+func sampleHash[T string | []byte](level int, msg T) uint64 {
+	h := (fnvOffset ^ uint64(level)) * fnvPrime
+	h = fnvAdd(h, msg)
+	return (h ^ 0xff) * fnvPrime // separates msg from the value
+}
+
+// samplingWriter drops the entries the sampler rejects before any sink
+// formats them. The message and key field are read from the raw JSON entry.
+type samplingWriter struct {
+	s   *sampler
+	key []byte // `,"UE_ID":`, or nil
+	w   log.Writer
+}
+
+func newSamplingWriter(s Sampling, w log.Writer) *samplingWriter {
+	sw := &samplingWriter{s: newSampler(s), w: w}
+	if s.Key != "" {
+		sw.key = appendJSONString([]byte(`,`), s.Key)
+		sw.key = append(sw.key, ':')
+	}
+	return sw
+}
+
+// messageKey marks the message, which phuslu writes last when not empty.
+var messageKey = []byte(`,"message":"`)
+
+// WriteEntry implements log.Writer. Fatal and panic entries are never
+// sampled.
+func (sw *samplingWriter) WriteEntry(e *log.Entry) (int, error) {
+	if e.Level == log.FatalLevel || e.Level == log.PanicLevel {
+		return sw.w.WriteEntry(e)
+	}
+	buf := e.Value()
+	var msg []byte
+	end := len(buf)
+	if i := bytes.LastIndex(buf, messageKey); i >= 0 {
+		// a quote inside a string value is escaped, so this is the message
+		msg, end = buf[i+len(messageKey):max(len(buf)-3, i+len(messageKey))], i
+	}
+	h := sampleHash(int(e.Level), msg)
+	if sw.key != nil {
+		h = fnvAdd(h, jsonFieldValue(buf[:end], sw.key))
+	}
+	if !sw.s.keep(h, time.Now().UnixNano()) {
+		return len(buf), nil
+	}
+	return sw.w.WriteEntry(e)
+}
+
+// jsonFieldValue returns the raw value of the field starting with key in
+// the JSON object buf, or nil.
+func jsonFieldValue(buf, key []byte) []byte {
+	i := bytes.Index(buf, key)
+	if i < 0 {
+		return nil
+	}
+	v := buf[i+len(key):]
+	if len(v) > 0 && v[0] == '"' {
+		for j := 1; j < len(v); j++ {
+			switch v[j] {
+			case '\\':
+				j++
+			case '"':
+				return v[:j+1]
+			}
+		}
+		return v
+	}
+	if j := bytes.IndexAny(v, ",}"); j >= 0 {
+		return v[:j]
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestSampler(t *testing.T) {
+	s := newSampler(Sampling{Tick: time.Second, First: 2, Thereafter: 3})
+	h := sampleHash(int(log.InfoLevel), "Packet processed successfully")
+	now := time.Now().UnixNano()
+
+	var kept []int
+	for n := 1; n <= 10; n++ {
+		if s.keep(h, now) {
+			kept = append(kept, n)
+		}
+	}
+	if got := fmt.Sprint(kept); got != "[1 2 5 8]" {
+		t.Errorf("kept entries %s, want [1 2 5 8]", got)
+	}
+	if !s.keep(h, now+int64(time.Second)) {
+		t.Error("first entry of the next tick was dropped")
+	}
+
+	drop := newSampler(Sampling{First: 1})
+	if !drop.keep(h, now) || drop.keep(h, now) || drop.keep(h, now) {
+		t.Error("Thereafter 0 should drop everything after First")
+	}
+}
+
+func TestSamplingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Reconfigure(log.InfoLevel, WithColor(ColorNever), WithWriter(&buf),
+		WithSampling(Sampling{Tick: time.Hour, First: 2, Thereafter: 5, Key: "UE_ID"})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+
+	before := GetSamplingStats()
+	for i := 0; i < 10; i++ {
+		PfcpLog.Info().Int("UE_ID", 1001).Msg("Packet processed successfully")
+		PfcpLog.Info().Int("UE_ID", 1002).Msg("Packet processed successfully")
+		PfcpLog.Info().Str("UE_ID", "imsi-001").Int("seq", i).Msg("Packet processed successfully")
+		PfcpLog.Warn().Int("UE_ID", 1001).Msg("Packet processed successfully")
+		PfcpLog.Info().Int("UE_ID", 1001).Msg("Session established")
+	}
+	// per key: entries 1, 2 and 7 of 10
+	lines := strings.Count(buf.String(), "\n")
+	if lines != 5*3 {
+		t.Errorf("logged %d lines, want 15:\n%s", lines, buf.String())
+	}
+	after := GetSamplingStats()
+	if after.Sampled-before.Sampled != 15 || after.Dropped-before.Dropped != 35 {
+		t.Errorf("stats %+v -> %+v, want 15 sampled and 35 dropped", before, after)
+	}
+}
+
+func TestJSONFieldValue(t *testing.T) {
+	entry := []byte(`{"time":"t","level":"info","component":"PFCP","UE_ID":1001,"imsi":"001\"01","ok":true}`)
+	for _, tt := range []struct{ key, want string }{
+		{`,"UE_ID":`, `1001`},
+		{`,"imsi":`, `"001\"01"`},
+		{`,"ok":`, `true`},
+		{`,"seid":`, ``},
+	} {
+		if got := string(jsonFieldValue(entry, []byte(tt.key))); got != tt.want {
+			t.Errorf("jsonFieldValue(%s) = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkSampling measures the hot path with sampling keyed by UE_ID,
+// where almost every entry is dropped before formatting.
+func BenchmarkSampling(b *testing.B) {
+	var out discardCounter
+	l := log.Logger{Level: log.InfoLevel, Writer: newSamplingWriter(
+		Sampling{First: 10, Thereafter: 100, Key: "UE_ID"},
+		newSinkWriter(buildOptions([]Option{WithColor(ColorNever), WithWriter(&out)})),
+	)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info().Str("component", "PFCP").Int("UE_ID", i%64).Msg("Packet processed successfully")
+	}
+}
+
+func TestSamplingKeepsFatal(t *testing.T) {
+	for _, key := range []string{"", "UE_ID"} {
+		var out syncBuffer
+		if err := Reconfigure(log.InfoLevel, WithColor(ColorNever), WithWriter(&out),
+			WithSampling(Sampling{Tick: time.Hour, First: 1, Key: key})); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = Reconfigure(log.InfoLevel, WithWriter(io.Discard)) })
+		for i := 0; i < 3; i++ {
+			func() {
+				defer func() { _ = recover() }()
+				defer RecoverAndLog(PfcpLog)
+				panic("boom")
+			}()
+		}
+		if n := strings.Count(out.String(), "| FATAL | PFCP  | panic: boom\n"); n != 3 {
+			t.Errorf("key %q: %d of 3 panics logged:\n%s", key, n, out.String())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// Multi-Sink Fan-Out
+// -------------------------------------------------------------
+
+// Sink formats.
+const (
+	FormatConsole = "console" // "timestamp | level | component | message key=value", the default
+	FormatJSON    = "json"    // one JSON object per line
+	FormatLogfmt  = "logfmt"  // `ts=... level=info component=PFCP msg="..." key=value`
+)
+
+// sinkFormats are the valid Sink.Format values.
+var sinkFormats = map[string]bool{"": true, FormatConsole: true, FormatJSON: true, FormatLogfmt: true}
+
+// Sink is one log destination with its own format and minimum level.
+type Sink struct {
+	Writer io.Writer
+	Format string    // FormatConsole (default), FormatJSON or FormatLogfmt
+	Level  log.Level // minimum level; zero takes every entry the logger passes
+	Color  ColorMode // console colors; ColorAuto follows WithColor, then the terminal
+}
+
+// sinkOutput is a sink's writer and minimum severity.
+type sinkOutput struct {
+	w           io.Writer
+	minSeverity int
+}
+
+// writeLine writes line to every output at or below severity, returning the
+// first error.
+func writeLine(outputs []sinkOutput, severity int, line []byte) (n int, err error) {
+	for _, out := range outputs {
+		if severity < out.minSeverity {
+			continue
+		}
+		if m, werr := out.w.Write(line); werr != nil && err == nil {
+			n, err = m, werr
+		} else if err == nil {
+			n = m
+		}
+	}
+	return n, err
+}
+
+// sinkGroup is every sink sharing one encoding of an entry, which is
+// produced once and written to each of them.
+type sinkGroup struct {
+	minSeverity int // lowest of the outputs, to skip the encoding entirely
+	outputs     []sinkOutput
+	write       func(e *log.Entry) (int, error)
+}
+
+// fanoutWriter sends each entry to all sink groups that want its level.
+type fanoutWriter struct {
+	groups []*sinkGroup
+}
+
+// WriteEntry implements log.Writer.
+func (f *fanoutWriter) WriteEntry(e *log.Entry) (n int, err error) {
+	severity := levelSeverity(e.Level)
+	for _, g := range f.groups {
+		if severity < g.minSeverity {
+			continue
+		}
+		if m, werr := g.write(e); werr != nil && err == nil {
+			n, err = m, werr
+		} else if err == nil {
+			n = m
+		}
+	}
+	return n, err
+}
+
+// newSinkWriter builds the writer for o's sinks, grouping the sinks by
+// their encoding: format, and colors for the console format.
+func newSinkWriter(o options) log.Writer {
+	sinks := o.sinks
+	if len(sinks) == 0 {
+		sinks = []Sink{{Writer: os.Stdout}}
+	}
+
+	type groupKey struct {
+		format string
+		color  bool
+	}
+	var f fanoutWriter
+	groups := make(map[groupKey]*sinkGroup)
+	for _, s := range sinks {
+		key := groupKey{format: o.formatOf(s)}
+		if key.format == FormatConsole {
+			key.color = useColor(o.colorFor(s), s.Writer)
+		}
+		out := sinkOutput{w: s.Writer}
+		if s.Level != 0 {
+			out.minSeverity = levelSeverity(s.Level)
+		}
+
+		g := groups[key]
+		if g == nil {
+			g = &sinkGroup{minSeverity: out.minSeverity}
+			switch key.format {
+			case FormatJSON:
+				g.write = newLineGroup(newJSONFormatter(o).appendLine, true, g, o.redact).WriteEntry
+			case FormatLogfmt:
+				g.write = newLineGroup(newLogfmtFormatter(o).appendLine, true, g, o.redact).WriteEntry
+			default:
+				g.write = newLineGroup(newConsoleFormatter(o, key.color).appendLine, key.color, g, o.redact).WriteEntry
+			}
+			groups[key] = g
+			f.groups = append(f.groups, g)
+		}
+		g.minSeverity = min(g.minSeverity, out.minSeverity)
+		g.outputs = append(g.outputs, out)
+	}
+
+	// one console sink taking everything: no fan-out needed
+	if len(sinks) == 1 && sinks[0].Level == 0 && o.formatOf(sinks[0]) == FormatConsole {
+		return newConsoleSink(o, sinks[0].Writer, useColor(o.colorFor(sinks[0]), sinks[0].Writer))
+	}
+	return &f
+}
+
+// newConsoleSink builds the console writer for a single output.
+func newConsoleSink(o options, out io.Writer, color bool) log.Writer {
+	// Files and pipes get plain labels with any stray escape sequences stripped.
+	if !color {
+		out = stripANSIWriter{out}
+	}
+
+	f := newConsoleFormatter(o, color)
+	format := f.Format
+	if r := o.redact; r != nil {
+		format = func(w io.Writer, args *log.FormatterArgs) (int, error) {
+			r.redactArgs(args)
+			return f.Format(w, args)
+		}
+	}
+	return &log.ConsoleWriter{
+		ColorOutput: false, // We'll manually colorize in consoleFormatter
+		Formatter:   format,
+		Writer:      out,
+	}
+}
+
+func newConsoleFormatter(o options, color bool) *consoleFormatter {
+	return &consoleFormatter{
+		time:  newTimeFormat(o.timeFormat, o.utc),
+		color: color,
+		theme: o.theme.compile(),
+	}
+}
+
+// newLineGroup returns a writer that formats each entry once with
+// appendLine and writes the line to every output of g at or below its
+// level. Unless color is set, escapes embedded in messages are stripped.
+// With r set, subscriber identities are redacted first.
+func newLineGroup(appendLine func([]byte, *log.FormatterArgs) []byte, color bool, g *sinkGroup, r *redactor) *log.ConsoleWriter {
+	return &log.ConsoleWriter{
+		ColorOutput: false, // We'll manually colorize in consoleFormatter
+		Formatter: func(_ io.Writer, args *log.FormatterArgs) (int, error) {
+			if r != nil {
+				r.redactArgs(args)
+			}
+			lb := lineBufferPool.Get().(*lineBuffer)
+			lb.b = appendLine(lb.b[:0], args)
+			line := lb.b
+			if !color && bytes.IndexByte(line, 0x1b) >= 0 {
+				// files and pipes: strip escapes embedded in messages
+				line = appendStripANSI(make([]byte, 0, len(line)), line)
+			}
+			n, err := writeLine(g.outputs, nameSeverity(args.Level), line)
+			if cap(lb.b) <= maxPooledLine {
+				lineBufferPool.Put(lb)
+			}
+			return n, err
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+// countingWriter counts Write calls.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestSinkFanOut(t *testing.T) {
+	var console, debugJSON, errorsOnly, all countingWriter
+	w := newSinkWriter(buildOptions([]Option{WithSinks(
+		Sink{Writer: &console, Level: log.InfoLevel, Color: ColorAlways},
+		Sink{Writer: &debugJSON, Format: FormatJSON, Level: log.DebugLevel},
+		Sink{Writer: &errorsOnly, Level: log.ErrorLevel, Color: ColorNever},
+		Sink{Writer: &all, Color: ColorNever},
+	)}))
+	l := log.Logger{Level: log.TraceLevel, Writer: w}
+	c := &ComponentLogger{component: "PFCP", context: log.NewContext(nil).Str("component", "PFCP").Value()}
+	l.Debug().Msg("debug")
+	l.Info().Msg("info")
+	l.Log().Str("level", "notice").Context(c.context).Msg("untyped")
+	e := l.Log().Str("level", "notice")
+	e.Level = NoticeLevel
+	e.Msg("notice")
+	l.Error().Str("seid", "7").Msg("error")
+
+	if got := console.String(); strings.Count(got, "\n") != 4 || !strings.Contains(got, "\033[32mINFO \033[0m | info\n") || !strings.Contains(got, "\033[34mNOTE \033[0m | notice\n") {
+		t.Errorf("console sink:\n%s", got)
+	}
+	if got := errorsOnly.String(); strings.Count(got, "\n") != 1 || !strings.HasSuffix(got, "ERR   | error seid=7\n") {
+		t.Errorf("error sink:\n%q", got)
+	}
+	if strings.Count(all.String(), "\n") != 5 || strings.Contains(all.String(), "\033") {
+		t.Errorf("catch-all sink:\n%s", all.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(debugJSON.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("json sink:\n%s", debugJSON.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[4]), &entry); err != nil || entry["level"] != "error" || entry["seid"] != "7" {
+		t.Errorf("json line %q: %v", lines[4], err)
+	}
+	// one Write per line and sink: nothing is split
+	if console.writes != 4 || errorsOnly.writes != 1 || all.writes != 5 || debugJSON.writes != 5 {
+		t.Errorf("writes = %d %d %d %d", console.writes, errorsOnly.writes, all.writes, debugJSON.writes)
+	}
+}
+
+func TestSinkGroups(t *testing.T) {
+	var a, b, c bytes.Buffer
+	f := newSinkWriter(buildOptions([]Option{WithColor(ColorNever), WithSinks(
+		Sink{Writer: &a},
+		Sink{Writer: &b, Level: log.WarnLevel},
+		Sink{Writer: &c, Format: FormatJSON},
+	)})).(*fanoutWriter)
+	// both plain console sinks share one encoding
+	if len(f.groups) != 2 || len(f.groups[0].outputs) != 2 || f.groups[0].minSeverity != 0 {
+		t.Errorf("groups = %+v", f.groups)
+	}
+
+	// a single console sink without a level needs no fan-out
+	if _, ok := newSinkWriter(buildOptions([]Option{WithWriter(&a)})).(*log.ConsoleWriter); !ok {
+		t.Errorf("single sink should be a plain console writer")
+	}
+}
+
+func BenchmarkSinkFanOut(b *testing.B) {
+	var console, file, errs discardCounter
+	l := log.Logger{Level: log.InfoLevel, Writer: newSinkWriter(buildOptions([]Option{WithSinks(
+		Sink{Writer: &console, Color: ColorAlways},
+		Sink{Writer: &file, Format: FormatJSON, Level: log.DebugLevel},
+		Sink{Writer: &errs, Level: log.ErrorLevel, Color: ColorAlways},
+	)}))}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info().Int("UE_ID", 1001).Msg("Packet processed successfully")
+	}
+}
+
+// discardCounter is io.Discard that counts bytes, so the benchmark writes
+// are not optimized away.
+type discardCounter struct{ n int }
+
+func (d *discardCounter) Write(p []byte) (int, error) {
+	d.n += len(p)
+	return len(p), nil
+}
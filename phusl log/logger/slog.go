@@ -0,0 +1,294 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// -------------------------------------------------------------
+// log/slog Handler
+// -------------------------------------------------------------
+
+// NewSlogHandler returns a slog.Handler that writes through the component
+// logger named component ("" for no component column), so slog output gets
+// the same format, sinks, levels and rate limits:
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler("MAIN")))
+//	slog.With("component", "NRF").Info("registered", "nf_id", id)
+//	slog.Default().WithGroup("PFCP").Info("association setup")
+//
+// Outside any group, two conventions move the entries to another
+// component's column and level. A "component" attribute given to With names
+// the component, creating it if needed. At most maxSlogComponents
+// components are created this way, since third-party code may set any
+// value; past that, an unknown name is kept as a plain attribute on entries
+// without a component logger, which still shows it in the component column
+// but levels it like the global logger. A WithGroup named after an existing
+// component, such as "PFCP", selects that component instead of opening a
+// group. Other groups become nested objects, so libraries can still
+// namespace their attributes with groups such as "req".
+//
+// Fields stored in the context by WithContext are added to each record.
+// slog levels map to trace (below debug), debug, info, notice (between info
+// and warn), warn and error.
+func NewSlogHandler(component string) slog.Handler {
+	c, _ := slogComponent(component, false)
+	return &slogHandler{c: c, groups: []slogGroup{{}}}
+}
+
+// slogHandler is immutable; WithAttrs and WithGroup return copies.
+type slogHandler struct {
+	c      *ComponentLogger
+	groups []slogGroup // groups[0] holds the top-level attributes
+}
+
+// slogGroup is a group opened by WithGroup and the attributes added in it,
+// encoded as `,"key":value` fields.
+type slogGroup struct {
+	name  string
+	attrs []byte
+}
+
+// maxSlogComponents caps the components created for "component"
+// attributes.
+const maxSlogComponents = 32
+
+// slogComponents counts the components created for "component" attributes;
+// levelStateMutex guards it.
+var slogComponents int
+
+// slogComponent returns the component logger named name, creating it on
+// first use; "" is a logger without a component column. A capped creation
+// fails once maxSlogComponents components have been created for attributes.
+func slogComponent(name string, capped bool) (*ComponentLogger, bool) {
+	if capped {
+		levelStateMutex.Lock()
+		_, known := components[name]
+		if !known && slogComponents == maxSlogComponents {
+			levelStateMutex.Unlock()
+			return nil, false
+		}
+		if !known {
+			slogComponents++
+		}
+		levelStateMutex.Unlock()
+	}
+	return componentLogger(name), true
+}
+
+// knownComponent returns a component logger named name, or nil if none has
+// been created.
+func knownComponent(name string) *ComponentLogger {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	return components[name]
+}
+
+// slogLevel maps a slog level to a built-in or registered level.
+func slogLevel(l slog.Level) log.Level {
+	switch {
+	case l < slog.LevelDebug:
+		return log.TraceLevel
+	case l < slog.LevelInfo:
+		return log.DebugLevel
+	case l == slog.LevelInfo:
+		return log.InfoLevel
+	case l < slog.LevelWarn:
+		return NoticeLevel
+	case l < slog.LevelError:
+		return log.WarnLevel
+	}
+	return log.ErrorLevel
+}
+
+// Enabled implements slog.Handler. It checks the component level only; the
+// rate limit is taken in Handle.
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return int64(levelSeverity(slogLevel(l))) >= h.c.minSeverity.Load()
+}
+
+var slogBufferPool = sync.Pool{New: func() any { return new([]byte) }}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := h.c.start(slogLevel(r.Level))
+	if e == nil {
+		return nil
+	}
+	e = e.Context(h.c.context)
+	if callerDepth != 0 && r.PC != 0 {
+		e = e.Str("caller", shortCaller(r.PC))
+	}
+	buf := slogBufferPool.Get().(*[]byte)
+	*buf = h.appendFields((*buf)[:0], ctx, r)
+	e.Context(*buf).Msg(r.Message)
+	if cap(*buf) <= maxPooledLine {
+		slogBufferPool.Put(buf)
+	}
+	return nil
+}
+
+// appendFields appends the attributes of h and r, nested in h's groups.
+// Groups left without any attribute are omitted.
+func (h *slogHandler) appendFields(b []byte, ctx context.Context, r slog.Record) []byte {
+	// encode the record's own fields first, then assemble after them
+	b = append(b, ContextFields(ctx)...)
+	r.Attrs(func(a slog.Attr) bool {
+		b = appendSlogAttr(b, a)
+		return true
+	})
+	n := len(b)
+
+	deep := len(h.groups) - 1 // innermost group with something in it
+	if n == 0 {
+		for deep > 0 && len(h.groups[deep].attrs) == 0 {
+			deep--
+		}
+	}
+	open := false
+	for i, g := range h.groups[:deep+1] {
+		if i > 0 {
+			b = appendFragment(b, appendJSONString([]byte(","), g.name), &open)
+			b = append(b, ":{"...)
+			open = true
+		}
+		b = appendFragment(b, g.attrs, &open)
+	}
+	b = appendFragment(b, b[:n], &open)
+	for range deep {
+		b = append(b, '}')
+	}
+	return b[n:]
+}
+
+// appendFragment appends `,"key":value` fields, without the leading comma
+// right after an opening brace.
+func appendFragment(b, frag []byte, open *bool) []byte {
+	if len(frag) == 0 {
+		return b
+	}
+	if *open {
+		frag, *open = frag[1:], false
+	}
+	return append(b, frag...)
+}
+
+// appendSlogAttr appends a as a `,"key":value` field. Empty attributes and
+// groups are skipped, and groups without a key are inlined.
+func appendSlogAttr(b []byte, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return b
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if a.Key == "" {
+			for _, ga := range attrs {
+				b = appendSlogAttr(b, ga)
+			}
+			return b
+		}
+		mark := len(b)
+		b = appendJSONString(append(b, ','), a.Key)
+		b = append(b, ":{"...)
+		start := len(b)
+		for _, ga := range attrs {
+			b = appendSlogAttr(b, ga)
+		}
+		if len(b) == start {
+			return b[:mark]
+		}
+		b = append(b[:start], b[start+1:]...) // drop the first member's comma
+		return append(b, '}')
+	}
+	b = appendJSONString(append(b, ','), a.Key)
+	b = append(b, ':')
+	return appendSlogValue(b, a.Value)
+}
+
+// appendSlogValue appends a resolved, non-group value as JSON. Durations
+// use time.Duration.String, times RFC 3339, errors their message; other
+// values are marshaled as JSON, or formatted with %v if that fails.
+func appendSlogValue(b []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return appendJSONString(b, v.String())
+	case slog.KindInt64:
+		return strconv.AppendInt(b, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(b, v.Uint64(), 10)
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return appendJSONString(b, strconv.FormatFloat(f, 'f', -1, 64))
+		}
+		return strconv.AppendFloat(b, f, 'f', -1, 64)
+	case slog.KindBool:
+		return strconv.AppendBool(b, v.Bool())
+	case slog.KindDuration:
+		return appendJSONString(b, v.Duration().String())
+	case slog.KindTime:
+		return appendJSONString(b, v.Time().Format(time.RFC3339Nano))
+	}
+	switch x := v.Any().(type) {
+	case error:
+		return appendJSONString(b, x.Error())
+	case nil:
+		return append(b, "null"...)
+	}
+	if data, err := json.Marshal(v.Any()); err == nil {
+		return append(b, data...)
+	}
+	return appendJSONString(b, fmt.Sprintf("%+v", v.Any()))
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := &slogHandler{c: h.c, groups: append([]slogGroup(nil), h.groups...)}
+	last := &h2.groups[len(h2.groups)-1]
+	last.attrs = last.attrs[:len(last.attrs):len(last.attrs)]
+	for _, a := range attrs {
+		if len(h2.groups) == 1 && a.Key == JSONComponentKey && a.Value.Kind() == slog.KindString {
+			c, ok := slogComponent(a.Value.String(), true)
+			if ok {
+				h2.c = c
+				continue
+			}
+			h2.c, _ = slogComponent("", false)
+		}
+		last.attrs = appendSlogAttr(last.attrs, a)
+	}
+	return h2
+}
+
+// WithGroup implements slog.Handler. Outside any group, a group named after
+// an existing component selects that component instead.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	if len(h.groups) == 1 {
+		if c := knownComponent(name); c != nil {
+			return &slogHandler{c: c, groups: h.groups}
+		}
+	}
+	return &slogHandler{c: h.c, groups: append(h.groups[:len(h.groups):len(h.groups)], slogGroup{name: name})}
+}
+
+// shortCaller returns "dir/file.go:line" for pc, like the caller column.
+func shortCaller(pc uintptr) string {
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return trimPath(f.File) + ":" + strconv.Itoa(f.Line)
+}
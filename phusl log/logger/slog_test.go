@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/phuslu/log"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	saved, savedLevel := globalLogger, GetLevel()
+	t.Cleanup(func() { globalLogger = saved; SetLevel(savedLevel) })
+	SetLevel(log.InfoLevel)
+
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		if strings.HasSuffix(t.Name(), "/zero-time") {
+			t.Skip("entries always carry the time they were written")
+		}
+		buf.Reset()
+		globalLogger = log.Logger{Level: log.TraceLevel, Writer: &log.IOWriter{Writer: &buf}}
+		return NewSlogHandler("")
+	}, func(t *testing.T) map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatalf("%v: %s", err, buf.String())
+		}
+		if msg, ok := m[JSONMessageKey]; ok {
+			delete(m, JSONMessageKey)
+			m[slog.MessageKey] = msg
+		}
+		return m
+	})
+}
+
+func TestSlogHandlerComponent(t *testing.T) {
+	var buf bytes.Buffer
+	useLevel(t, &buf, log.InfoLevel)
+
+	NewComponentLogger("PFCP")
+	l := slog.New(NewSlogHandler("MAIN"))
+	l.Info("started", "port", 8080)
+	l.With("component", "NRF").Info("registered", "nf_id", "smf-1")
+	l.With("component", "NRF").Debug("filtered out")
+	l.WithGroup("req").With("component", "grouped").Warn("kept as field")
+	l.Log(context.Background(), slog.LevelInfo+2, "notice")
+	l.Error("failed", "err", errors.New("boom"), "d", 1500*1e6)
+	l.WithGroup("PFCP").Info("association setup", "seid", 42)
+	l.WithGroup("req").WithGroup("PFCP").Info("nested", "id", 7)
+
+	// past the cap, unknown components stay attributes
+	levelStateMutex.Lock()
+	created := slogComponents
+	slogComponents = maxSlogComponents
+	levelStateMutex.Unlock()
+	l.With("component", "ue-208930000000003").Info("capped")
+	l.With("component", "NRF").Info("known")
+	levelStateMutex.Lock()
+	slogComponents = created
+	levelStateMutex.Unlock()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []struct{ level, suffix string }{
+		{"INFO ", "| MAIN  | started port=8080"},
+		{"INFO ", "| NRF   | registered nf_id=smf-1"},
+		{"WARN ", `| MAIN  | kept as field req={"component":"grouped"}`},
+		{"NOTE ", "| MAIN  | notice"},
+		{"ERR  ", "| MAIN  | failed err=boom d=1500000000"},
+		{"INFO ", "| PFCP  | association setup seid=42"},
+		{"INFO ", `| MAIN  | nested req={"PFCP":{"id":7}}`},
+		{"INFO ", "| ue-208930000000003 | capped"},
+		{"INFO ", "| NRF   | known"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w.level) || !strings.HasSuffix(lines[i], w.suffix) {
+			t.Errorf("line %d = %q, want level %q and suffix %q", i, lines[i], w.level, w.suffix)
+		}
+	}
+}
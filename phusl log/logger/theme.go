@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// -------------------------------------------------------------
+// Color Themes
+// -------------------------------------------------------------
+
+// LevelStyle is how one level is rendered on the console.
+type LevelStyle struct {
+	Label string // e.g. "INFO "; labels are padded to the widest one in the theme
+	Color string // ANSI SGR sequence, e.g. "\033[32m" or Color256(196)
+}
+
+// Theme configures console level labels and colors.
+type Theme struct {
+	// Levels is keyed by lower-case level name: "trace", "debug", "info",
+	// "notice", "warn", "error", "fatal", "panic" or any registered level.
+	Levels map[string]LevelStyle
+
+	// Components colors the component column, keyed by component name
+	// (e.g. "PFCP"). Components without an entry are not colored.
+	Components map[string]string
+}
+
+// Color256 returns the foreground escape for color n of the 256-color palette.
+func Color256(n uint8) string {
+	return "\033[38;5;" + strconv.Itoa(int(n)) + "m"
+}
+
+// Background256 returns the background escape for color n of the 256-color palette.
+func Background256(n uint8) string {
+	return "\033[48;5;" + strconv.Itoa(int(n)) + "m"
+}
+
+// TrueColor returns the 24-bit foreground escape for r, g, b.
+func TrueColor(r, g, b uint8) string {
+	return "\033[38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
+
+// BackgroundTrueColor returns the 24-bit background escape for r, g, b.
+func BackgroundTrueColor(r, g, b uint8) string {
+	return "\033[48;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
+
+// DefaultTheme is the classic 8-color scheme shared with the zap bench/logger.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Levels: map[string]LevelStyle{
+			"trace":  {"TRACE", colorMagenta},
+			"debug":  {"DEBUG", colorCyan},
+			"info":   {"INFO ", colorGreen},
+			"notice": {"NOTE ", colorBlue},
+			"warn":   {"WARN ", colorYellow},
+			"error":  {"ERR  ", colorRed},
+			"fatal":  {"FATAL", "\033[41m\033[37m"}, // White text on a red background
+			"panic":  {"PANIC", "\033[45m\033[37m"}, // White text on a magenta background
+		},
+	}
+}
+
+// HighContrastTheme uses the 256-color palette so errors and PFCP traffic
+// stand out in dense terminals.
+func HighContrastTheme() *Theme {
+	bold := "\033[1m"
+	return &Theme{
+		Levels: map[string]LevelStyle{
+			"trace":  {"TRACE", Color256(244)},
+			"debug":  {"DEBUG", Color256(45)},
+			"info":   {"INFO ", Color256(40)},
+			"notice": {"NOTE ", Color256(39)},
+			"warn":   {"WARN ", bold + Color256(214)},
+			"error":  {"ERR  ", bold + Color256(231) + Background256(160)},
+			"fatal":  {"FATAL", bold + Color256(231) + Background256(88)},
+			"panic":  {"PANIC", bold + Color256(231) + Background256(90)},
+		},
+		Components: map[string]string{
+			"PFCP":   bold + Color256(208),
+			"SESS":   Color256(177),
+			"CHARGE": Color256(220),
+			"SBI":    Color256(117),
+			"NWDAF":  Color256(156),
+		},
+	}
+}
+
+// defaultTheme is DefaultTheme compiled once for formatters without a theme.
+var defaultTheme = DefaultTheme().compile()
+
+// theme is a Theme prepared for the formatter: labels padded to one width and
+// every registered level resolvable by name.
+type theme struct {
+	levels     map[string]LevelStyle
+	components map[string]string
+	width      int // label width of levels the theme does not know
+}
+
+// compile prepares t for formatting; a nil Theme means DefaultTheme.
+func (t *Theme) compile() *theme {
+	if t == nil {
+		t = DefaultTheme()
+	}
+	ct := &theme{
+		levels:     make(map[string]LevelStyle, len(t.Levels)),
+		components: make(map[string]string, len(t.Components)),
+		width:      5,
+	}
+	for _, s := range t.Levels {
+		if n := utf8.RuneCountInString(s.Label); n > ct.width {
+			ct.width = n
+		}
+	}
+	for name, s := range t.Levels {
+		s.Label = string(appendPadded(nil, s.Label, ct.width))
+		ct.levels[strings.ToLower(name)] = s
+	}
+	for name, c := range t.Components {
+		ct.components[name] = c
+	}
+	return ct
+}
+
+// level returns the color and label for the level name in args.Level.
+func (t *theme) level(name string) (colorCode, label string) {
+	if s, ok := t.levels[name]; ok {
+		return s.Color, s.Label
+	}
+	return colorWhite, string(appendPadded(nil, strings.ToUpper(name), t.width))
+}
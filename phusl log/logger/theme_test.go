@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThemeCompile(t *testing.T) {
+	th := (&Theme{Levels: map[string]LevelStyle{
+		"info":  {"INFO", colorGreen},
+		"ERROR": {"ERROR!", TrueColor(255, 0, 0)},
+	}}).compile()
+
+	if c, label := th.level("info"); c != colorGreen || label != "INFO  " {
+		t.Errorf("info = %q %q, want labels padded to the widest", c, label)
+	}
+	if c, label := th.level("error"); c != "\033[38;2;255;0;0m" || label != "ERROR!" {
+		t.Errorf("error = %q %q", c, label)
+	}
+	if c, label := th.level("notice"); c != colorWhite || label != "NOTICE" {
+		t.Errorf("unknown level = %q %q", c, label)
+	}
+}
+
+func TestThemeComponentColor(t *testing.T) {
+	f := &consoleFormatter{color: true, theme: HighContrastTheme().compile()}
+	args := consoleFormatterArgs()
+	args.KeyValues[0].Value = "PFCP"
+	got := string(f.appendLine(nil, args))
+	if !strings.Contains(got, "\033[38;5;40mINFO \033[0m | \033[1m\033[38;5;208mPFCP \033[0m | ") {
+		t.Errorf("high contrast line = %q", got)
+	}
+
+	f.color = false
+	if got := string(f.appendLine(nil, args)); strings.Contains(got, "\033") {
+		t.Errorf("plain line has escapes: %q", got)
+	}
+}
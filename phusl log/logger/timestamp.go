@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// -------------------------------------------------------------
+// Timestamp Layouts (cached per second)
+// -------------------------------------------------------------
+
+// Timestamp formats accepted by WithTimeFormat. Any other value is used as a
+// time.Format layout.
+const (
+	// TimeFormatDefault prints phuslu's own RFC3339 timestamp unchanged.
+	TimeFormatDefault = ""
+	// TimeFormatZap matches the layout of the zap bench/logger.
+	TimeFormatZap = "2006-01-02 | 15:04:05.000"
+	// TimeFormatRFC3339 is RFC3339 with milliseconds.
+	TimeFormatRFC3339 = "2006-01-02T15:04:05.000Z07:00"
+	// TimeFormatEpochMillis prints Unix time in milliseconds.
+	TimeFormatEpochMillis = "epoch_ms"
+	// TimeFormatEpochNanos prints Unix time in nanoseconds. phuslu records
+	// milliseconds, so the last six digits are always zero.
+	TimeFormatEpochNanos = "epoch_ns"
+)
+
+// timeFormat renders the phuslu timestamp (args.Time, e.g.
+// "2025-03-08T12:34:56.789Z" or "2025-03-08T12:34:56.789+05:30") in another
+// layout. Everything except the milliseconds only changes once per second, so
+// the formatted text before and after the fraction is cached and reused until
+// the second rolls over; a custom layout then costs two appends per line.
+type timeFormat struct {
+	format string         // one of the TimeFormat* constants or a layout
+	loc    *time.Location // time.Local or time.UTC
+
+	head, tail string // layout split around the fractional seconds
+	frac       int    // digits of fractional seconds, 0 if none
+	slow       bool   // layout uses ".999" style fractions, formatted per line
+
+	cache atomic.Pointer[timeCache]
+}
+
+// timeCache holds the rendering of one wall-clock second.
+type timeCache struct {
+	second string // args.Time up to the seconds, e.g. "2025-03-08T12:34:56"
+	zone   string // args.Time zone suffix, e.g. "Z"
+	unix   int64
+	head   []byte
+	tail   []byte
+}
+
+// newTimeFormat returns nil when args.Time can be printed as-is.
+func newTimeFormat(format string, utc bool) *timeFormat {
+	if format == TimeFormatDefault {
+		if !utc {
+			return nil
+		}
+		format = TimeFormatRFC3339
+	}
+	f := &timeFormat{format: format, loc: time.Local}
+	if utc {
+		f.loc = time.UTC
+	}
+	switch format {
+	case TimeFormatEpochMillis, TimeFormatEpochNanos:
+		return f
+	}
+	f.head, f.tail, f.frac, f.slow = splitFraction(format)
+	return f
+}
+
+// splitFraction splits layout around its first fractional-second element,
+// e.g. "15:04:05.000 MST" -> ("15:04:05.", " MST", 3).
+func splitFraction(layout string) (head, tail string, digits int, slow bool) {
+	for i := 0; i+1 < len(layout); i++ {
+		if (layout[i] != '.' && layout[i] != ',') || (layout[i+1] != '0' && layout[i+1] != '9') {
+			continue
+		}
+		// the fraction must follow the seconds element
+		if i < 2 || layout[i-2:i] != "05" {
+			continue
+		}
+		if layout[i+1] == '9' {
+			return layout, "", 0, true
+		}
+		j := i + 1
+		for j < len(layout) && layout[j] == '0' {
+			j++
+		}
+		return layout[:i+1], layout[j:], j - i - 1, false
+	}
+	return layout, "", 0, false
+}
+
+// appendTime appends ts (args.Time) in the configured format.
+func (f *timeFormat) appendTime(b []byte, ts string) []byte {
+	// "2006-01-02T15:04:05.000" followed by "Z" or "+07:00"
+	if len(ts) < 24 || ts[19] != '.' {
+		return append(b, ts...)
+	}
+	c := f.cache.Load()
+	if c == nil || c.second != ts[:19] || c.zone != ts[23:] {
+		var ok bool
+		if c, ok = f.render(ts); !ok {
+			return append(b, ts...)
+		}
+		f.cache.Store(c)
+	}
+	millis := ts[20:23]
+
+	switch f.format {
+	case TimeFormatEpochMillis:
+		b = strconv.AppendInt(b, c.unix, 10)
+		return append(b, millis...)
+	case TimeFormatEpochNanos:
+		b = strconv.AppendInt(b, c.unix, 10)
+		b = append(b, millis...)
+		return append(b, "000000"...)
+	}
+	if f.slow {
+		ms, _ := strconv.Atoi(millis)
+		return time.Unix(c.unix, int64(ms)*int64(time.Millisecond)).In(f.loc).AppendFormat(b, f.format)
+	}
+
+	b = append(b, c.head...)
+	if f.frac > 0 {
+		if f.frac <= 3 {
+			b = append(b, millis[:f.frac]...)
+		} else {
+			b = append(b, millis...)
+			b = append(b, "000000"[:f.frac-3]...)
+		}
+	}
+	return append(b, c.tail...)
+}
+
+// render parses the second and zone of ts and formats the cached parts.
+func (f *timeFormat) render(ts string) (*timeCache, bool) {
+	year, ok1 := atoi(ts[0:4])
+	month, ok2 := atoi(ts[5:7])
+	day, ok3 := atoi(ts[8:10])
+	hour, ok4 := atoi(ts[11:13])
+	minute, ok5 := atoi(ts[14:16])
+	second, ok6 := atoi(ts[17:19])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) {
+		return nil, false
+	}
+
+	// zone is "Z" or "+hh:mm"/"-hh:mm"
+	var offset int
+	zone := ts[23:]
+	switch {
+	case zone == "Z":
+	case len(zone) == 6 && (zone[0] == '+' || zone[0] == '-'):
+		zh, okh := atoi(zone[1:3])
+		zm, okm := atoi(zone[4:6])
+		if !okh || !okm {
+			return nil, false
+		}
+		offset = zh*3600 + zm*60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+	default:
+		return nil, false
+	}
+
+	unix := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC).Unix() - int64(offset)
+	c := &timeCache{
+		second: strings.Clone(ts[:19]),
+		zone:   strings.Clone(zone),
+		unix:   unix,
+	}
+	if !f.slow {
+		t := time.Unix(unix, 0).In(f.loc)
+		c.head = t.AppendFormat(nil, f.head)
+		c.tail = t.AppendFormat(nil, f.tail)
+	}
+	return c, true
+}
+
+// atoi parses a short run of ASCII digits.
+func atoi(s string) (n int, ok bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, true
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	const ts = "2025-03-08T12:34:56.789+05:30"
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{TimeFormatZap, "2025-03-08 | 07:04:56.789"},
+		{TimeFormatRFC3339, "2025-03-08T07:04:56.789Z"},
+		{TimeFormatEpochMillis, "1741417496789"},
+		{TimeFormatEpochNanos, "1741417496789000000"},
+		{"15:04:05.000000 MST", "07:04:56.789000 UTC"},
+		{"15:04:05,0", "07:04:56,7"},
+		{"Jan _2 15:04:05", "Mar  8 07:04:56"},
+		{time.StampMilli, "Mar  8 07:04:56.789"},
+		{"15:04:05.999", "07:04:56.789"},
+	} {
+		f := newTimeFormat(tc.format, true)
+		// twice: once rendering the second, once from the cache
+		for i := 0; i < 2; i++ {
+			if got := string(f.appendTime(nil, ts)); got != tc.want {
+				t.Errorf("%q pass %d: got %q, want %q", tc.format, i, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestTimeFormatDefault(t *testing.T) {
+	if f := newTimeFormat(TimeFormatDefault, false); f != nil {
+		t.Errorf("default local format should print args.Time as-is")
+	}
+	f := newTimeFormat(TimeFormatDefault, true)
+	if got := string(f.appendTime(nil, "2025-03-08T12:34:56.789-01:00")); got != "2025-03-08T13:34:56.789Z" {
+		t.Errorf("UTC default = %q", got)
+	}
+	// unexpected shapes pass through untouched
+	if got := string(f.appendTime(nil, "1741437296")); got != "1741437296" {
+		t.Errorf("unparsed time = %q", got)
+	}
+}
+
+func TestTimeFormatCachedZeroAlloc(t *testing.T) {
+	f := newTimeFormat(TimeFormatZap, false)
+	b := make([]byte, 0, 64)
+	f.appendTime(b, "2025-03-08T12:34:56.789Z")
+	allocs := testing.AllocsPerRun(1000, func() {
+		b = f.appendTime(b[:0], "2025-03-08T12:34:56.790Z")
+	})
+	if allocs != 0 {
+		t.Errorf("cached appendTime allocates %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkTimeFormatZap(b *testing.B) {
+	f := newTimeFormat(TimeFormatZap, false)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = f.appendTime(buf[:0], "2025-03-08T12:34:56.789Z")
+	}
+}
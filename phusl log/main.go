@@ -2,7 +2,10 @@ package main
 
 import (
 	"bench/logger"
+	"flag"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/phuslu/log"
@@ -14,7 +17,21 @@ type result struct {
 }
 
 func main() {
-	// 1) Initialize the logger
+	// 1) Initialize the logger from the config file, if one is given, and the
+	// LOG_* environment variables
+	configPath := flag.String("config", "", "logger configuration file (YAML or JSON)")
+	flag.Parse()
+	c, err := logger.ConfigFromEnv()
+	if *configPath != "" {
+		c, err = logger.LoadConfig(*configPath)
+	}
+	if err == nil {
+		err = logger.ApplyConfig(c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	results := []result{}
 	logger.Ready()
 
@@ -28,6 +45,62 @@ func main() {
 		name:     "Benchmarkformatted",
 		duration: e.Sub(s),
 	})
+	// Component loop with the caller (file:line) resolved for every entry,
+	// as logger.WithCaller does
+	s, e = benchmarkcomponent(logger.CallerLogger(logger.MainLog, 0))
+	results = append(results, result{
+		name:     "Caller",
+		duration: e.Sub(s),
+	})
+	s, e = benchmarkcomponent(logger.MainLog)
+	results = append(results, result{
+		name:     "Component",
+		duration: e.Sub(s),
+	})
+	s, e = benchmarkcomponentformatted(logger.MainLog)
+	results = append(results, result{
+		name:     "Componentformatted",
+		duration: e.Sub(s),
+	})
+
+	// Encoder cost per output format, discarding the output so only the
+	// formatting is measured
+	for _, format := range []string{logger.FormatConsole, logger.FormatJSON, logger.FormatLogfmt} {
+		if err := logger.Reconfigure(logger.GetLevel(), logger.WithFormat(format), logger.WithWriter(io.Discard)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		s, e = benchmarkcomponent(logger.MainLog)
+		results = append(results, result{
+			name:     "Format " + format,
+			duration: e.Sub(s),
+		})
+	}
+
+	// Sampling the hot-path message: the first 100 per second, then 1 in 100
+	sampling := logger.Sampling{Tick: time.Second, First: 100, Thereafter: 100}
+	if err := logger.Reconfigure(logger.GetLevel(), logger.WithSampling(sampling), logger.WithWriter(io.Discard)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	s, e = benchmarkcomponent(logger.MainLog)
+	results = append(results, result{
+		name:     "Sampled",
+		duration: e.Sub(s),
+	})
+
+	// Redacting subscriber identities; entries without any, like these, only
+	// pay for the scan
+	redaction := logger.Redaction{Keep: 5}
+	if err := logger.Reconfigure(logger.GetLevel(), logger.WithRedaction(redaction), logger.WithWriter(io.Discard)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	s, e = benchmarkcomponent(logger.MainLog)
+	results = append(results, result{
+		name:     "Redacted",
+		duration: e.Sub(s),
+	})
 
 	fmt.Println("\nSummary of Logging Performance")
 	fmt.Println("------------------------------------------------")
@@ -42,7 +115,7 @@ func main() {
 
 }
 
-func benchmarknormal(logger log.Logger) (time.Time, time.Time) {
+func benchmarknormal(logger *log.Logger) (time.Time, time.Time) {
 	start := time.Now()
 	for i := 0; i < 500000; i++ {
 		logger.Info().Msg("Packet processed successfully UE_ID 1001 " + "iteration")
@@ -51,7 +124,7 @@ func benchmarknormal(logger log.Logger) (time.Time, time.Time) {
 	return start, end
 }
 
-func benchmarkformatted(logger log.Logger) (time.Time, time.Time) {
+func benchmarkformatted(logger *log.Logger) (time.Time, time.Time) {
 	start := time.Now()
 	for i := 0; i < 500000; i++ {
 		logger.Info().Msgf("Packet processed successfully UE_ID 1001 iteration: %d ", i)
@@ -59,56 +132,21 @@ func benchmarkformatted(logger log.Logger) (time.Time, time.Time) {
 	end := time.Now()
 	return start, end
 }
-// ---------------------------------------------------------------------
-
-// func main() {
-// 	var results []result
-// 	logger.Initialize(log.InfoLevel)
-
-// 	// 2. Create "sub-loggers" for each component
-// 	mainLog := logger.NewComponentLogger("MAIN")
-// 	// nfLog := logger.NewComponentLogger("NF")
-// 	// initLog := logger.NewComponentLogger("INIT")
-
-// 	// 3. Use them
-// 	s, e := benchmarknormal(mainLog)
-// 	results = append(results, result{
-// 		name:     "Benchmarknormal",
-// 		duration: e.Sub(s),
-// 	})
-
-// 	s, e = benchmarkformatted(mainLog)
-// 	results = append(results, result{
-// 		name:     "Benchmarkformatted",
-// 		duration: e.Sub(s),
-// 	})
 
-// 	fmt.Println("\nSummary of Logging Performance")
-// 	fmt.Println("------------------------------------------------")
-// 	fmt.Printf("| %-20s | %-15s |\n", "Writer", "Duration (s)")
-// 	fmt.Println("------------------------------------------------")
-// 	for _, r := range results {
-// 		// Convert duration to seconds with 6 decimal places
-// 		secs := float64(r.duration.Milliseconds()) / 1000.0
-// 		fmt.Printf("| %-20s | %-15.6f |\n", r.name, secs)
-// 	}
-// 	fmt.Println("------------------------------------------------")
-// }
-
-// func benchmarknormal(logger *logger.SubLogger) (time.Time, time.Time) {
-// 	start := time.Now()
-// 	for i := 0; i < 500000; i++ {
-// 		logger.Info().Msg("Packet processed successfully UE_ID 1001 " + "iteration")
-// 	}
-// 	end := time.Now()
-// 	return start, end
-// }
+func benchmarkcomponent(logger *logger.ComponentLogger) (time.Time, time.Time) {
+	start := time.Now()
+	for i := 0; i < 500000; i++ {
+		logger.Info().Msg("Packet processed successfully UE_ID 1001 " + "iteration")
+	}
+	end := time.Now()
+	return start, end
+}
 
-// func benchmarkformatted(logger *logger.SubLogger) (time.Time, time.Time) {
-// 	start := time.Now()
-// 	for i := 0; i < 500000; i++ {
-// 		logger.Info().Msgf("Packet processed successfully UE_ID 1001 iteration: %d ", i)
-// 	}
-// 	end := time.Now()
-// 	return start, end
-// }
+func benchmarkcomponentformatted(logger *logger.ComponentLogger) (time.Time, time.Time) {
+	start := time.Now()
+	for i := 0; i < 500000; i++ {
+		logger.Info().Msgf("Packet processed successfully UE_ID 1001 iteration: %d ", i)
+	}
+	end := time.Now()
+	return start, end
+}
@@ -2,6 +2,14 @@ module bench
 
 go 1.24.0
 
-require go.uber.org/zap v1.27.0
+require (
+	bench/logbase v0.0.0
+	go.uber.org/zap v1.27.0
+)
 
-require go.uber.org/multierr v1.10.0 // indirect
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace bench/logbase => ../logbase
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"bytes"
+	stdlog "log"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Capturing io.Writer and Standard Library Output
+// -------------------------------------------------------------
+
+// LineWriter is an io.Writer that logs each line written to it as an entry
+// of a component logger, for libraries that only take an io.Writer:
+//
+//	gin.DefaultWriter = &logger.LineWriter{Logger: logger.GinLog, DetectLevel: true}
+//	gin.DefaultErrorWriter = &logger.LineWriter{Logger: logger.GinLog, Level: zapcore.ErrorLevel}
+//
+// A line is logged once its newline arrives; Flush logs a trailing partial
+// line. A partial line reaching maxCapturedLine bytes is logged in pieces of
+// that size, so output that never ends its lines cannot grow the buffer
+// without bound. Blank lines are dropped. It is safe for concurrent use.
+type LineWriter struct {
+	// Logger receives the entries; nil means MainLog.
+	Logger Sugared
+	// Level is the level of each entry; the zero value is info. DPanic,
+	// panic and fatal are logged as error, so captured output never stops
+	// the process.
+	Level zapcore.Level
+	// DetectLevel takes the level from a leading "[WARN]", "[GIN-debug]",
+	// "ERROR:" style prefix when there is one, and strips the prefix.
+	DetectLevel bool
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+// maxCapturedLine is the most LineWriter holds of a line without newline.
+const maxCapturedLine = 64 << 10
+
+// Write implements io.Writer. It never fails.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	if i := bytes.IndexByte(p, '\n'); i >= 0 && len(w.partial) > 0 {
+		w.partial = append(w.partial, p[:i]...)
+		w.logLine(string(w.partial))
+		w.partial, p = w.partial[:0], p[i+1:]
+	}
+	for {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(string(p[:i]))
+		p = p[i+1:]
+	}
+	w.partial = append(w.partial, p...)
+	for len(w.partial) >= maxCapturedLine {
+		w.logLine(string(w.partial[:maxCapturedLine]))
+		w.partial = w.partial[:copy(w.partial, w.partial[maxCapturedLine:])]
+	}
+	if cap(w.partial) > 2*maxCapturedLine {
+		w.partial = append([]byte(nil), w.partial...) // let a huge Write go
+	}
+	return n, nil
+}
+
+// Flush logs a partial line left by the last Write, if any.
+func (w *LineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.logLine(string(w.partial))
+		w.partial = w.partial[:0]
+	}
+}
+
+// logLine logs one line without its newline; the caller holds w.mu.
+func (w *LineWriter) logLine(line string) {
+	line = strings.TrimRight(line, "\r \t")
+	level := w.Level
+	if w.DetectLevel {
+		if l, rest, ok := detectLevel(line); ok {
+			level, line = l, rest
+		}
+	}
+	if line == "" {
+		return
+	}
+	if level > zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+	var l Sugared = MainLog
+	if w.Logger != nil {
+		l = w.Logger
+	}
+	// straight to the core: a caller would point at this writer, not the
+	// library
+	ent := zapcore.Entry{Level: level, Time: time.Now(), LoggerName: l.Desugar().Name(), Message: line}
+	if ce := l.Desugar().Core().Check(ent, nil); ce != nil {
+		ce.Write()
+	}
+}
+
+// detectLevel recognizes up to two leading level tags, such as "[WARN] ",
+// "WARNING: " or Gin's "[GIN-debug] [WARNING] ", and returns the level of
+// the last one with the rest of the line.
+func detectLevel(line string) (level zapcore.Level, rest string, ok bool) {
+	for range 2 {
+		tag, after := levelTag(line)
+		l, found := prefixLevel(tag)
+		if !found {
+			break
+		}
+		level, line, ok = l, strings.TrimLeft(after, " \t"), true
+	}
+	return level, line, ok
+}
+
+// levelTag splits a leading "[tag]" or "tag:" off line.
+func levelTag(line string) (tag, rest string) {
+	if strings.HasPrefix(line, "[") {
+		if i := strings.IndexByte(line, ']'); i > 0 {
+			return line[1:i], line[i+1:]
+		}
+		return "", line
+	}
+	if i := strings.IndexByte(line, ':'); i > 0 && i <= len("critical") {
+		return line[:i], line[i+1:]
+	}
+	return "", line
+}
+
+// prefixLevel maps a level tag to a level. Fatal, panic and critical tags
+// map to error.
+func prefixLevel(tag string) (zapcore.Level, bool) {
+	tag = strings.TrimPrefix(strings.ToLower(tag), "gin-")
+	switch tag {
+	case "trace":
+		return TraceLevel, true
+	case "debug":
+		return zapcore.DebugLevel, true
+	case "info":
+		return zapcore.InfoLevel, true
+	case "warn", "warning":
+		return zapcore.WarnLevel, true
+	case "error", "err":
+		return zapcore.ErrorLevel, true
+	case "fatal", "panic", "crit", "critical":
+		return zapcore.ErrorLevel, true
+	case "":
+		return 0, false
+	}
+	if l, err := ParseLevel(tag); err == nil && l <= firstCustomLevel {
+		return l, true
+	}
+	return 0, false
+}
+
+// NewStdLogger returns a standard library *log.Logger writing each message
+// as an entry of l at level, with level prefixes detected. It fits
+// http.Server.ErrorLog and other APIs that take a *log.Logger:
+//
+//	srv := &http.Server{ErrorLog: logger.NewStdLogger(logger.SBILog, zapcore.ErrorLevel)}
+func NewStdLogger(l Sugared, level zapcore.Level) *stdlog.Logger {
+	return stdlog.New(&LineWriter{Logger: l, Level: level, DetectLevel: true}, "", 0)
+}
+
+// RedirectStdLog sends the output of the standard library log package to
+// l at level, with level prefixes detected, until the returned function
+// restores the previous output, flags and prefix. Calling slog.SetDefault
+// afterwards takes the log package over again.
+func RedirectStdLog(l Sugared, level zapcore.Level) (restore func()) {
+	w, flags, prefix := stdlog.Writer(), stdlog.Flags(), stdlog.Prefix()
+	stdlog.SetOutput(&LineWriter{Logger: l, Level: level, DetectLevel: true})
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+	return func() {
+		stdlog.SetOutput(w)
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+	}
+}
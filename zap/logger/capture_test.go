@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"fmt"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLineWriter(t *testing.T) {
+	out := useOutput(t, zapcore.DebugLevel)
+
+	w := &LineWriter{Logger: GinLog, DetectLevel: true}
+	fmt.Fprint(w, "[GIN-debug] Listening and serving HTTP on :8080\n[GIN] 200 | GET /health\n\n")
+	fmt.Fprint(w, "[GIN-debug] [WARNING] Running in \"debug\" mode")
+	fmt.Fprint(w, "\r\nERROR: upstream gone\nhttp: TLS handshake error\n[FATAL] not fatal here\npartial")
+	w.Flush()
+
+	checkLines(t, out.String(),
+		"| DEBUG | GIN   | Listening and serving HTTP on :8080",
+		"| INFO  | GIN   | [GIN] 200 | GET /health",
+		`| WARN  | GIN   | Running in "debug" mode`,
+		"| ERR   | GIN   | upstream gone",
+		"| INFO  | GIN   | http: TLS handshake error",
+		"| ERR   | GIN   | not fatal here",
+		"| INFO  | GIN   | partial",
+	)
+}
+
+func TestLineWriterLongLine(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	// output without newlines is logged in bounded pieces
+	w := &LineWriter{Logger: GinLog}
+	chunk := strings.Repeat("x", 1000)
+	for i := 0; i < 150; i++ {
+		fmt.Fprint(w, chunk)
+	}
+	if len(w.partial) >= maxCapturedLine || cap(w.partial) > 2*maxCapturedLine {
+		t.Errorf("holding %d bytes, capacity %d", len(w.partial), cap(w.partial))
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	got := 0
+	for _, l := range lines {
+		got += strings.Count(l, "x")
+	}
+	if got != 150*len(chunk) {
+		t.Errorf("logged %d bytes, want %d", got, 150*len(chunk))
+	}
+}
+
+func TestStdLog(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	NewStdLogger(SBILog, zapcore.ErrorLevel).Printf("http: TLS handshake error from %s: EOF", "10.0.0.1:4711")
+	restore := RedirectStdLog(MainLog, zapcore.InfoLevel)
+	stdlog.Print("started")
+	stdlog.Print("[DEBUG] filtered out")
+	restore()
+
+	checkLines(t, out.String(),
+		"| ERR   | SBI   | http: TLS handshake error from 10.0.0.1:4711: EOF",
+		"| INFO  | MAIN  | started",
+	)
+	if stdlog.Flags() != stdlog.LstdFlags {
+		t.Errorf("restore left flags %d", stdlog.Flags())
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"go.uber.org/zap/buffer"
+)
+
+// -------------------------------------------------------------
+// Color Detection & ANSI Stripping
+// -------------------------------------------------------------
+
+// ColorMode controls ANSI colors in console output.
+type ColorMode int
+
+const (
+	// ColorAuto colors a sink only if it is a terminal. NO_COLOR disables
+	// and FORCE_COLOR enables colors regardless of the destination.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors every console sink.
+	ColorAlways
+	// ColorNever prints plain level labels everywhere.
+	ColorNever
+)
+
+// useColor decides whether console output written to w is colorized.
+func useColor(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); v != "" && v != "0" && v != "false" {
+		return true
+	}
+	if a, ok := w.(*AsyncWriter); ok {
+		w = a.Writer // the queue's output decides
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device such as a TTY.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+var stripPool = buffer.NewPool()
+
+// stripANSIWriter removes ANSI escape sequences before writing to a file or
+// pipe, catching colors embedded in messages (e.g. captured Gin output).
+type stripANSIWriter struct {
+	w io.Writer
+}
+
+// Write implements io.Writer. Lines without an ESC byte are passed through.
+func (s stripANSIWriter) Write(p []byte) (int, error) {
+	if bytes.IndexByte(p, 0x1b) < 0 {
+		return s.w.Write(p)
+	}
+	buf := stripPool.Get()
+	defer buf.Free()
+	for i := 0; i < len(p); i++ {
+		if p[i] != 0x1b {
+			buf.AppendByte(p[i])
+			continue
+		}
+		if i+1 < len(p) && p[i+1] == '[' {
+			// CSI: parameter and intermediate bytes, then one final byte 0x40-0x7e
+			i += 2
+			for i < len(p) && (p[i] < 0x40 || p[i] > 0x7e) {
+				i++
+			}
+		} else {
+			// ESC, optional intermediate bytes 0x20-0x2f, one final byte
+			i++
+			for i < len(p) && p[i] >= 0x20 && p[i] <= 0x2f {
+				i++
+			}
+		}
+	}
+	_, err := s.w.Write(buf.Bytes())
+	return len(p), err
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestApplyConfigCallerIgnored(t *testing.T) {
+	if err := Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "smf.log")
+	c := &Config{Caller: true, Components: "PFCP=debug", Sinks: []SinkConfig{{Type: "file", Path: path}}}
+	if err := ApplyConfig(c); err != nil {
+		t.Fatalf("a caller change should not fail the reload: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard), WithComponentLevels(""))
+	})
+	NewComponentLogger("PFCP").Debug("applied")
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if !strings.Contains(got, "CFG   | caller: true ignored") || !strings.Contains(got, "PFCP  | applied") || strings.Contains(got, "config_test.go") {
+		t.Errorf("reload with caller: true:\n%s", got)
+	}
+}
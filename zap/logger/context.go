@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// context.Context Fields
+// -------------------------------------------------------------
+
+// contextKey holds the log fields of a context.Context.
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying fields, in addition to the
+// fields of ctx itself. Like the sugared logger's With, fields are
+// key-value pairs such as "request_id", id, "UE_ID", ue, or zap.Field
+// values. Loggers from ComponentLogger.Ctx for the returned context add
+// them all.
+func WithContext(ctx context.Context, fields ...any) context.Context {
+	prev := ContextFields(ctx)
+	return context.WithValue(ctx, contextKey{}, append(prev[:len(prev):len(prev)], contextFields(fields)...))
+}
+
+// ContextFields returns the fields stored in ctx by WithContext.
+func ContextFields(ctx context.Context) []zapcore.Field {
+	fields, _ := ctx.Value(contextKey{}).([]zapcore.Field)
+	return fields
+}
+
+// Ctx returns a logger for this component that adds the fields stored in
+// ctx by WithContext to every entry, e.g. PfcpLog.Ctx(ctx).Infow("..."). The
+// fields are added as entries are written, so building a logger per request
+// does not copy its encoder, and a context without fields returns the
+// component's own sugared logger.
+func (c *ComponentLogger) Ctx(ctx context.Context) *zap.SugaredLogger {
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return c.SugaredLogger
+	}
+	return c.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &ctxCore{Core: core, fields: fields}
+	}))
+}
+
+// ctxCore adds the fields of a context to each entry it writes.
+type ctxCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+}
+
+// With implements zapcore.Core. The context fields go into the new core
+// first, keeping them ahead of fields, as they would be in the entries.
+func (c *ctxCore) With(fields []zapcore.Field) zapcore.Core {
+	return c.Core.With(append(c.fields[:len(c.fields):len(c.fields)], fields...))
+}
+
+// Check implements zapcore.Core. The wrapped core checks the entry again,
+// rate limit and sampling included, when it is written.
+func (c *ctxCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *ctxCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	writeChecked(c.Core, ent, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+	return nil
+}
+
+// contextFields converts key-value pairs and zap.Fields to fields, naming
+// bad keys like the sugared logger does.
+func contextFields(args []any) []zapcore.Field {
+	fields := make([]zapcore.Field, 0, len(args)/2+1)
+	for i := 0; i < len(args); i++ {
+		if f, ok := args[i].(zapcore.Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		if i == len(args)-1 {
+			fields = append(fields, zap.Any("ignored", args[i]))
+			break
+		}
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+		fields = append(fields, zap.Any(key, args[i+1]))
+		i++
+	}
+	return fields
+}
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestContextLogger(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	ctx := WithContext(context.Background(), "request_id", "r-1")
+	ctx = WithContext(ctx, "UE_ID", 1001)
+	if l := PfcpLog.Ctx(context.Background()); l != PfcpLog.SugaredLogger {
+		t.Error("a context without fields built a new logger")
+	}
+	PfcpLog.Ctx(ctx).Infow("Session created", "seid", 42)
+	PfcpLog.Ctx(ctx).With("node", "upf-1").Info("Association setup")
+	PfcpLog.Ctx(ctx).Debug("filtered")
+
+	checkLines(t, out.String(),
+		`| INFO  | PFCP  | Session created | {"request_id": "r-1", "UE_ID": 1001, "seid": 42}`,
+		`| INFO  | PFCP  | Association setup | {"request_id": "r-1", "UE_ID": 1001, "node": "upf-1"}`,
+	)
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Swappable Core + Atomic Level
+// -------------------------------------------------------------
+
+// atomicLevel is the minimum level of every logger from Initialize. Unlike
+// zap.AtomicLevel it filters on severity, so registered levels sort between
+// the built-in ones.
+type atomicLevel struct {
+	level    atomic.Int32
+	severity atomic.Int64
+}
+
+// set changes the minimum level.
+func (a *atomicLevel) set(level zapcore.Level) {
+	a.severity.Store(int64(levelSeverity(level)))
+	a.level.Store(int32(level))
+}
+
+// get returns the minimum level.
+func (a *atomicLevel) get() zapcore.Level {
+	return zapcore.Level(a.level.Load())
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (a *atomicLevel) Enabled(level zapcore.Level) bool {
+	return int64(levelSeverity(level)) >= a.severity.Load()
+}
+
+// coreBox lets atomic.Pointer hold an interface value. active counts the
+// entries checked against it and not yet written, so a replaced core can be
+// drained before its files are closed.
+type coreBox struct {
+	core   zapcore.Core
+	direct zapcore.Core // the outputs of core without sampling and dedup
+	active atomic.Int64
+}
+
+// setCore installs core, writing to the outputs of direct, as the current
+// core and waits for the entries checked against the previous one, for at
+// most drainTimeout, so its outputs can be closed.
+func setCore(core, direct zapcore.Core) {
+	if old := currentCore.Swap(&coreBox{core: core, direct: direct}); old != nil {
+		drain(&old.active)
+	}
+}
+
+// writeDirect writes ent to the current outputs, whatever the logger
+// levels, sampling and dedup, for reports that must not be lost.
+func writeDirect(ent zapcore.Entry) {
+	if currentCore.Load() == nil {
+		return
+	}
+	b := (&swapCore{current: &currentCore}).acquire()
+	defer b.active.Add(-1)
+	writeChecked(b.direct, ent, nil)
+}
+
+// A coreBox is added last to each entry checked against it, to count the
+// entry done once the cores before it have written it.
+
+// Enabled implements zapcore.LevelEnabler.
+func (b *coreBox) Enabled(zapcore.Level) bool { return false }
+
+// With implements zapcore.Core.
+func (b *coreBox) With([]zapcore.Field) zapcore.Core { return b }
+
+// Check implements zapcore.Core.
+func (b *coreBox) Check(_ zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (b *coreBox) Write(zapcore.Entry, []zapcore.Field) error {
+	b.active.Add(-1)
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (b *coreBox) Sync() error { return nil }
+
+// boundCore is the current core with a swapCore's fields added.
+type boundCore struct {
+	base *coreBox
+	core zapcore.Core
+}
+
+// swapCore is the core behind every logger from Initialize. Reconfigure
+// replaces the encoder and output underneath it; level filters before
+// anything is encoded.
+type swapCore struct {
+	level   *atomicLevel
+	current *atomic.Pointer[coreBox]
+	limit   *atomic.Pointer[rateBucket] // component rate limit, nil for the global logger
+
+	fields []zapcore.Field           // from With, added to the current core
+	bound  atomic.Pointer[boundCore] // current core with fields, rebuilt after a swap
+}
+
+// core returns the current core with c's fields.
+func (c *swapCore) core() zapcore.Core {
+	return c.coreOf(c.current.Load())
+}
+
+// acquire returns the current core box counted as active. The second load
+// makes sure that setCore either waits for the entry or the entry goes to
+// the new core.
+func (c *swapCore) acquire() *coreBox {
+	for {
+		b := c.current.Load()
+		b.active.Add(1)
+		if c.current.Load() == b {
+			return b
+		}
+		b.active.Add(-1)
+	}
+}
+
+// coreOf returns the core of base with c's fields.
+func (c *swapCore) coreOf(base *coreBox) zapcore.Core {
+	if len(c.fields) == 0 {
+		return base.core
+	}
+	if b := c.bound.Load(); b != nil && b.base == base {
+		return b.core
+	}
+	b := &boundCore{base: base, core: base.core.With(c.fields)}
+	c.bound.Store(b)
+	return b.core
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+// Level reports the minimum level for zap.Logger.Level.
+func (c *swapCore) Level() zapcore.Level {
+	return c.level.get()
+}
+
+// With implements zapcore.Core.
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &swapCore{
+		level:   c.level,
+		current: c.current,
+		limit:   c.limit,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || c.limited(ent) {
+		return ce
+	}
+	// the current core decides too, so its sampler sees the entry
+	base := c.acquire()
+	if ce = c.coreOf(base).Check(ent, ce); ce == nil {
+		base.active.Add(-1)
+		return nil
+	}
+	return ce.AddCore(ent, base)
+}
+
+// Write implements zapcore.Core.
+func (c *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(ent, fields)
+}
+
+// Sync implements zapcore.Core.
+func (c *swapCore) Sync() error {
+	return c.core().Sync()
+}
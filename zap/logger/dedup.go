@@ -0,0 +1,201 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Repeated-Message Suppression
+// -------------------------------------------------------------
+
+// DefaultDedupHold is the longest a run of repeats is held back when
+// WithDedup is given no hold time.
+const DefaultDedupHold = 10 * time.Second
+
+// WithDedup collapses identical consecutive entries (same component, level,
+// message and fields; the time may differ): the first is logged, the
+// repeats are counted and reported as one "last message repeated N times
+// over D" entry when a different entry arrives, after maxHold
+// (DefaultDedupHold if zero) while repeats continue, or at Reconfigure and
+// Close.
+func WithDedup(maxHold time.Duration) Option {
+	return func(o *options) {
+		if maxHold <= 0 {
+			maxHold = DefaultDedupHold
+		}
+		o.dedup = maxHold
+	}
+}
+
+// repeatedMessage is the message of a repeat summary.
+func repeatedMessage(n int, d time.Duration) string {
+	if d >= time.Millisecond {
+		d = d.Round(time.Millisecond)
+	} else {
+		d = d.Round(time.Microsecond)
+	}
+	if n == 1 {
+		return fmt.Sprintf("last message repeated 1 time over %s", d)
+	}
+	return fmt.Sprintf("last message repeated %d times over %s", n, d)
+}
+
+// activeDeduper is the dedup stage of the last configure, if any; guarded by
+// configMutex.
+var activeDeduper *deduper
+
+// deduper holds the last entry of every dedupCore sharing it, and the count
+// of its repeats.
+type deduper struct {
+	hold time.Duration
+
+	mu      sync.Mutex
+	held    bool
+	core    zapcore.Core // the core below the dedupCore that wrote last
+	context []byte       // encoded With fields of that dedupCore
+	ent     zapcore.Entry
+	fields  []byte    // encoded fields of ent
+	scratch []byte    // fields of the entry being compared
+	latest  time.Time // of the latest repeat
+	repeats int
+	run     uint64 // incremented per run of repeats, to ignore stale timers
+	timer   *time.Timer
+}
+
+func newDeduper(hold time.Duration) *deduper {
+	return &deduper{hold: hold}
+}
+
+// dedupCore drops entries equal to the previous one, including the fields
+// added by With, and writes the rest to the core below. Fields are compared
+// as encoded, so values of any type compare safely, and a held entry keeps
+// no reference to values the caller may change.
+type dedupCore struct {
+	zapcore.Core
+	d       *deduper
+	context []byte // With fields, encoded
+}
+
+// With implements zapcore.Core.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:    c.Core.With(fields),
+		d:       c.d,
+		context: encodeFields(c.context[:len(c.context):len(c.context)], fields),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	d := c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if ent.Level >= zapcore.DPanicLevel && ent.Level <= zapcore.FatalLevel {
+		// never held back
+		d.flushLocked()
+		d.held = false
+		writeChecked(c.Core, ent, fields)
+		return nil
+	}
+	d.scratch = encodeFields(d.scratch[:0], fields)
+	if d.held && d.same(c, ent) {
+		d.repeats++
+		d.latest = ent.Time
+		if d.repeats == 1 {
+			d.run++
+			run := d.run
+			d.timer = time.AfterFunc(d.hold, func() { d.expire(run) })
+		}
+		return nil
+	}
+	d.flushLocked()
+	d.held, d.core, d.context, d.ent = true, c.Core, c.context, ent
+	d.fields, d.scratch = d.scratch, d.fields
+	writeChecked(c.Core, ent, fields)
+	return nil
+}
+
+// writeChecked writes through core's Check, so a sampler below still
+// decides. Write errors go to os.Stderr, as for zap.Logger.
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.ErrorOutput = stderrOutput
+		ce.Write(fields...)
+	}
+}
+
+var stderrOutput = zapcore.Lock(os.Stderr)
+
+// same reports whether an entry written to c, with its fields encoded in
+// d.scratch, equals the held one.
+func (d *deduper) same(c *dedupCore, ent zapcore.Entry) bool {
+	return ent.Level == d.ent.Level && ent.Message == d.ent.Message && ent.LoggerName == d.ent.LoggerName &&
+		bytes.Equal(c.context, d.context) && bytes.Equal(d.scratch, d.fields)
+}
+
+// fieldsEncoder encodes the fields compared by dedup; the entry itself
+// has no keys, so only the fields are written.
+var fieldsEncoder = zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+
+// encodeFields appends fields, encoded, to b.
+func encodeFields(b []byte, fields []zapcore.Field) []byte {
+	if len(fields) == 0 {
+		return b
+	}
+	buf, err := fieldsEncoder.EncodeEntry(zapcore.Entry{}, fields)
+	if err != nil {
+		return append(b, err.Error()...)
+	}
+	b = append(b, buf.Bytes()...)
+	buf.Free()
+	return b
+}
+
+// expire writes the summary of a run still pending after the hold time.
+// Repeats after it keep being counted, for the next summary.
+func (d *deduper) expire(run uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.run == run && d.repeats > 0 {
+		d.flushLocked()
+	}
+}
+
+// flush writes the summary of pending repeats, if any.
+func (d *deduper) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushLocked()
+}
+
+func (d *deduper) flushLocked() {
+	if d.repeats == 0 {
+		return
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	writeChecked(d.core, zapcore.Entry{
+		Level:      d.ent.Level,
+		Time:       time.Now(),
+		LoggerName: d.ent.LoggerName,
+		Message:    repeatedMessage(d.repeats, d.latest.Sub(d.ent.Time)),
+	}, nil)
+	d.repeats = 0
+	d.ent.Time = d.latest
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDedup(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithDedup(time.Hour))
+
+	for i := 0; i < 5; i++ {
+		SBILog.Warnw("Retrying NF registration", "nf", "AMF")
+	}
+	SBILog.Warnw("Retrying NF registration", "nf", "UDM")
+	PfcpLog.Info("Association setup")
+	PfcpLog.Info("Association setup")
+	PfcpLog.With("seid", 1).Info("Association setup")
+	NfLog.Log(NoticeLevel, "Profile updated")
+	NfLog.Log(NoticeLevel, "Profile updated")
+	if err := Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{
+		`\| WARN  \| SBI   \| Retrying NF registration \| \{"nf": "AMF"\}$`,
+		`\| WARN  \| SBI   \| last message repeated 4 times over \d+(\.\d+)?[µnm]?s$`,
+		`\| WARN  \| SBI   \| Retrying NF registration \| \{"nf": "UDM"\}$`,
+		`\| INFO  \| PFCP  \| Association setup$`,
+		`\| INFO  \| PFCP  \| last message repeated 1 time over`,
+		`\| INFO  \| PFCP  \| Association setup \| \{"seid": 1\}$`,
+		`\| NOTE  \| NF    \| Profile updated$`,
+		`\| NOTE  \| NF    \| last message repeated 1 time over`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, w := range want {
+		if !regexp.MustCompile(w).MatchString(lines[i]) {
+			t.Errorf("line %d = %q, want match for %s", i, lines[i], w)
+		}
+	}
+}
+
+// upfList is a Stringer of an uncomparable type.
+type upfList []string
+
+func (l upfList) String() string { return strings.Join(l, ",") }
+
+func TestDedupFieldValues(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithDedup(time.Hour))
+
+	PfcpLog.Infow("UPFs selected", zap.Stringer("upfs", upfList{"upf-1"}))
+	PfcpLog.Infow("UPFs selected", zap.Stringer("upfs", upfList{"upf-1"}))
+	// a value changed after the call that logged it is a new entry
+	state := map[string]int{"sessions": 1}
+	PfcpLog.Infow("UPF state", "state", state)
+	state["sessions"] = 2
+	PfcpLog.Infow("UPF state", "state", state)
+	if err := Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		`| UPFs selected | {"upfs": "upf-1"}` + "\n",
+		"| PFCP  | last message repeated 1 time over",
+		`| UPF state | {"state": {"sessions":1}}` + "\n",
+		`| UPF state | {"state": {"sessions":2}}` + "\n",
+	} {
+		if strings.Count(got, want) != 1 {
+			t.Errorf("want %q once in:\n%s", want, got)
+		}
+	}
+}
+
+func TestDedupHold(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithDedup(20*time.Millisecond))
+	for i := 0; i < 3; i++ {
+		PfcpLog.Error("Heartbeat timeout")
+	}
+	if n := strings.Count(out.String(), "\n"); n != 1 {
+		t.Fatalf("repeats were written before the hold time:\n%s", out.String())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "repeated 2 times") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "| ERR   | PFCP  | last message repeated 2 times over") {
+		t.Fatalf("no summary after the hold time:\n%s", out.String())
+	}
+}
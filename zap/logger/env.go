@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"bench/logbase"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Environment Overrides
+// -------------------------------------------------------------
+
+// Environment variables that override the code defaults and the config
+// file; see logbase.EnvLevel.
+const (
+	EnvLevel           = logbase.EnvLevel
+	EnvFormat          = logbase.EnvFormat
+	EnvOutput          = logbase.EnvOutput
+	EnvColor           = logbase.EnvColor
+	EnvComponentLevels = logbase.EnvComponentLevels
+	EnvRateLimits      = logbase.EnvRateLimits
+)
+
+// ConfigFromEnv returns the default configuration with the LOG_* overrides
+// applied, for programs without a config file.
+func ConfigFromEnv() (*Config, error) {
+	c := &Config{}
+	if err := overrideFromEnv(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// overrideFromEnv validates the LOG_* variables and applies them to c,
+// reporting every bad value at once.
+func overrideFromEnv(c *Config) error {
+	return logbase.ApplyEnv(c, configChecker{})
+}
+
+// envOptions returns level and the options for the LOG_* overrides, which
+// go after the code's own. It opens any LOG_OUTPUT files; files is nil
+// unless LOG_OUTPUT replaces the outputs. The error reports the variables
+// left out as invalid, and LOG_OUTPUT files that failed to open.
+func envOptions(level zapcore.Level) (zapcore.Level, []Option, []io.Closer, error) {
+	var c Config
+	err := overrideFromEnv(&c)
+	var opts []Option
+	if c.Level != "" {
+		level, _ = ParseLevel(c.Level)
+	}
+	if c.Color != "" {
+		color, _ := parseColorMode(c.Color)
+		opts = append(opts, WithColor(color))
+	}
+	if c.Components != "" {
+		opts = append(opts, WithComponentLevels(c.Components))
+	}
+	if c.RateLimits != "" {
+		opts = append(opts, WithRateLimits(c.RateLimits))
+	}
+	var files []io.Closer
+	if len(c.Sinks) > 0 {
+		sinks, opened, openErr := openSinks(c.Sinks, false)
+		if openErr != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", EnvOutput, openErr))
+		} else {
+			opts = append(opts, WithSinks(sinks...))
+			files = append([]io.Closer{}, opened...)
+		}
+	}
+	if c.Format != "" {
+		opts = append(opts, overrideFormat(c.Format))
+	}
+	return level, opts, files, err
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOverrideFromEnv(t *testing.T) {
+	c, err := ParseConfig([]byte("theme: high-contrast\ntimestamp: {format: rfc3339}\nsinks: [{type: stdout}]"), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvLevel, "debug")
+	t.Setenv(EnvColor, "always")
+	t.Setenv(EnvComponentLevels, "GIN=warn")
+	t.Setenv(EnvOutput, "stderr, /tmp/smf.log")
+	t.Setenv(EnvFormat, "console")
+	if err := overrideFromEnv(c); err != nil {
+		t.Fatal(err)
+	}
+	want := []SinkConfig{{Type: "stderr", Format: "console"}, {Type: "file", Path: "/tmp/smf.log", Format: "console"}}
+	if c.Level != "debug" || c.Color != "always" || c.Components != "GIN=warn" || len(c.Sinks) != 2 || c.Sinks[0] != want[0] || c.Sinks[1] != want[1] {
+		t.Errorf("config = %+v", c)
+	}
+	// settings without a variable keep the config file value
+	if c.Theme != "high-contrast" || c.Timestamp.Format != "rfc3339" {
+		t.Errorf("config file values lost: %+v", c)
+	}
+}
+
+func TestOverrideFromEnvErrors(t *testing.T) {
+	t.Setenv(EnvLevel, "loud")
+	t.Setenv(EnvColor, "pink")
+	t.Setenv(EnvComponentLevels, "PFCP")
+	t.Setenv(EnvRateLimits, "GIN=fast")
+	t.Setenv(EnvOutput, "stdout,,x")
+	t.Setenv(EnvFormat, "xml")
+	_, err := ConfigFromEnv()
+	if err == nil {
+		t.Fatal("invalid environment accepted")
+	}
+	for _, name := range []string{EnvLevel, EnvColor, EnvComponentLevels, EnvRateLimits, EnvOutput, EnvFormat} {
+		if !strings.Contains(err.Error(), name+"=") {
+			t.Errorf("error does not name %s:\n%v", name, err)
+		}
+	}
+}
+
+func TestInitializeBadEnv(t *testing.T) {
+	t.Setenv(EnvLevel, "loud")
+	t.Setenv(EnvOutput, "stdout,,x")
+	t.Setenv(EnvFormat, "logfmt")
+	stderr := captureStderr(t)
+	var out bytes.Buffer
+	Initialize(zapcore.DebugLevel, WithSinks(Sink{Writer: &out, Format: FormatJSON}))
+	t.Cleanup(func() { _ = Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard)) })
+
+	if got := stderr(); !strings.Contains(got, `LOG_LEVEL="loud": unknown level`) || !strings.Contains(got, `LOG_OUTPUT="stdout,,x": empty output`) {
+		t.Errorf("stderr does not report the bad variables:\n%s", got)
+	}
+	if GetLevel() != zapcore.DebugLevel {
+		t.Errorf("level %v, want the code's debug", GetLevel())
+	}
+	// LOG_FORMAT overrides the formats set in code, as it does in a config file
+	PfcpLog.Debug("started")
+	if got := out.String(); !strings.Contains(got, "level=debug component=PFCP msg=started") {
+		t.Errorf("LOG_FORMAT=logfmt not applied:\n%s", got)
+	}
+}
@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Error Fields
+// -------------------------------------------------------------
+
+// Keys of the fields added by Err and ErrStack.
+const (
+	ErrorKey      = "error"       // err.Error()
+	ErrorChainKey = "error_chain" // own messages of the wrapped errors, depth first
+	ErrorStackKey = "error_stack" // "function (dir/file.go:line)" frames
+)
+
+// maxErrorChain and maxErrorStack bound the chain and stack of one error.
+const (
+	maxErrorChain = 32
+	maxErrorStack = 32
+)
+
+// Err returns a field logging err's message under "error", like zap.Error,
+// and the messages of the errors it wraps, through Unwrap and errors.Join,
+// under "error_chain". JSON sinks get the chain as an array; console sinks
+// print it below the entry, one "caused by" per line:
+//
+//	logger.PduSessLog.Errorw("create SM context failed", logger.Err(err))
+//
+// A nil err adds nothing.
+func Err(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Inline(errorFields{msg: err.Error(), chain: errorChain(err)})
+}
+
+// ErrStack is Err plus the stack of its caller under "error_stack", for
+// errors worth the cost of capturing it.
+func ErrStack(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Inline(errorFields{msg: err.Error(), chain: errorChain(err), stack: callerStack(2)})
+}
+
+// errorFields are the fields of Err and ErrStack, rendered when the field
+// is created so later changes to the error do not show.
+type errorFields struct {
+	msg   string
+	chain []string
+	stack []string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (f errorFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(ErrorKey, f.msg)
+	if len(f.chain) > 0 {
+		_ = enc.AddArray(ErrorChainKey, stringArray(f.chain))
+	}
+	if len(f.stack) > 0 {
+		_ = enc.AddArray(ErrorStackKey, stringArray(f.stack))
+	}
+	return nil
+}
+
+// stringArray encodes strings as an array.
+type stringArray []string
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a stringArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, s := range a {
+		enc.AppendString(s)
+	}
+	return nil
+}
+
+// errorChain returns the causes of err, depth first: the own message of
+// each error it wraps through Unwrap or errors.Join, leaving out the text of
+// that error's causes, which follow it in the chain. So
+// fmt.Errorf("dial upf: %w", err) contributes "dial upf", and an
+// errors.Join, whose message is only those of its errors, contributes none.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		for _, e := range unwrapErrors(err) {
+			if e == nil || len(chain) == maxErrorChain {
+				continue
+			}
+			if msg := ownMessage(e); msg != "" {
+				chain = append(chain, msg)
+			}
+			walk(e)
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// unwrapErrors returns the errors err wraps, which may include nils.
+func unwrapErrors(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// ownMessage returns the message of err up to where that of the first
+// error it wraps begins, without the ": " before it: "dial upf" for
+// fmt.Errorf("dial upf: %w", err), nothing for errors.Join. A message not
+// containing its cause's is returned whole.
+func ownMessage(err error) string {
+	msg := err.Error()
+	for _, e := range unwrapErrors(err) {
+		if e == nil {
+			continue
+		}
+		if i := strings.Index(msg, e.Error()); i >= 0 {
+			return strings.TrimSuffix(msg[:i], ": ")
+		}
+		break
+	}
+	return msg
+}
+
+// callerStack returns the stack above skip frames as
+// "function (dir/file.go:line)" strings, without the frames of the runtime,
+// such as runtime.main and runtime.goexit.
+func callerStack(skip int) []string {
+	var pcs [maxErrorStack]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip+1, pcs[:])])
+	var stack []string
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") {
+			stack = append(stack, f.Function+" ("+trimPath(f.File)+":"+strconv.Itoa(f.Line)+")")
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// trimPath shortens a file path to its directory and file, "dir/file.go".
+func trimPath(file string) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			return file[j+1:]
+		}
+	}
+	return file
+}
+
+// errorLineBreak continues a message spanning lines, such as an errors.Join
+// wrapped in other text, below its "caused by" line.
+const errorLineBreak = "\n        "
+
+// errorLinesEncoder is a console encoder that shows the message of an Err
+// or ErrStack field as error=..., and its chain and stack on indented lines
+// below the entry.
+type errorLinesEncoder struct {
+	zapcore.Encoder
+}
+
+// Clone implements zapcore.Encoder.
+func (e errorLinesEncoder) Clone() zapcore.Encoder {
+	return errorLinesEncoder{e.Encoder.Clone()}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e errorLinesEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	var errs []errorFields
+	for i, f := range fields {
+		ef, ok := f.Interface.(errorFields)
+		if !ok || f.Type != zapcore.InlineMarshalerType {
+			continue
+		}
+		if errs == nil {
+			fields = append([]zapcore.Field(nil), fields...)
+		}
+		errs = append(errs, ef)
+		fields[i] = zap.String(ErrorKey, ef.msg)
+	}
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return buf, err
+	}
+	for _, ef := range errs {
+		for _, l := range ef.chain {
+			buf.AppendString("    caused by: " + strings.ReplaceAll(l, "\n", errorLineBreak) + "\n")
+		}
+		for _, l := range ef.stack {
+			buf.AppendString("    at " + l + "\n")
+		}
+	}
+	return buf, nil
+}
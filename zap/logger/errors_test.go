@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var errRefused = errors.New("connection refused")
+
+func TestErrorChain(t *testing.T) {
+	dial := fmt.Errorf("dial upf-1: %w", errRefused)
+	for _, tt := range []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"plain", errRefused, nil},
+		{"single", fmt.Errorf("create SM context: %w", dial), []string{"dial upf-1", "connection refused"}},
+		{"join", errors.Join(dial, errors.New("upf-2 unreachable")), []string{"dial upf-1", "connection refused", "upf-2 unreachable"}},
+		{"multi %w", fmt.Errorf("N4: %w", fmt.Errorf("upfs: %w, %w", dial, errRefused)), []string{"upfs", "dial upf-1", "connection refused", "connection refused"}},
+	} {
+		if got := errorChain(tt.err); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: errorChain = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestErrConsole(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	err := fmt.Errorf("create SM context: %w", fmt.Errorf("dial upf-1: %w", errRefused))
+	PduSessLog.Errorw("failed", Err(err), "pdu_session_id", 5)
+	PduSessLog.Errorw("stacked", ErrStack(errRefused))
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) < 5 {
+		t.Fatalf("output:\n%s", out.String())
+	}
+	for i, want := range []string{
+		`| SESS  | failed | {"error": "create SM context: dial upf-1: connection refused", "pdu_session_id": 5}`,
+		"    caused by: dial upf-1",
+		"    caused by: connection refused",
+		`| SESS  | stacked | {"error": "connection refused"}`,
+		"    at bench/logger.TestErrConsole (logger/errors_test.go:",
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+	if strings.Contains(out.String(), "at runtime.") {
+		t.Errorf("stack has runtime frames:\n%s", out.String())
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// parityEntry is the entry of the phuslu backend's JSON and logfmt formatter
+// tests, whose expected lines these tests share.
+func parityEntry(level zapcore.Level) zapcore.Entry {
+	return zapcore.Entry{
+		Level:      level,
+		Time:       time.Date(2026, 10, 17, 5, 22, 23, 348e6, time.FixedZone("IST", 5*3600+1800)),
+		LoggerName: "PFCP",
+		Caller:     zapcore.EntryCaller{Defined: true, File: "/src/smf/pfcp.go", Line: 42},
+		Message:    "Packet processed",
+	}
+}
+
+func TestJSONParity(t *testing.T) {
+	buf, err := newJSONEncoder(options{utc: true}).EncodeEntry(parityEntry(NoticeLevel), []zapcore.Field{
+		zap.Int("seid", 42),
+		zap.String("ue", "imsi\t\"001\"\x01"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"level":"notice","time":"2026-10-16T23:52:23.348Z","component":"PFCP","caller":"smf/pfcp.go:42","message":"Packet processed","seid":42,"ue":"imsi\t\"001\"\u0001"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithFormat(FormatJSON))
+	PduSessLog.Infow("Session created", "UE_ID", 1001)
+
+	line := out.String()
+	for _, key := range []string{`{"level":"info","time":"`, `,"component":"SESS","message":"Session created","UE_ID":1001}`} {
+		if !strings.Contains(line, key) {
+			t.Errorf("%s does not contain %s", line, key)
+		}
+	}
+}
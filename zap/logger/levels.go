@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Registered Levels (TRACE, NOTICE, ...)
+// -------------------------------------------------------------
+
+// zapcore.Level has no room between its built-in levels, so registered
+// levels take values below DebugLevel and are ordered by severity instead.
+// Initialize filters on severity, so they sort correctly against the
+// built-in levels; log at them with SugaredLogger.Log/Logf/Logw.
+const (
+	// TraceLevel is below debug: very verbose, per-packet detail.
+	TraceLevel zapcore.Level = zapcore.DebugLevel - 1
+
+	// firstCustomLevel is the first value handed out by RegisterLevel.
+	firstCustomLevel = TraceLevel - 1
+)
+
+// NoticeLevel sits between info and warn: normal but significant events.
+var NoticeLevel = RegisterLevel("notice", zapcore.InfoLevel)
+
+// customLevel is one registered level.
+type customLevel struct {
+	name     string
+	severity int
+}
+
+// levelTable is replaced as a whole on registration, so the hot path reads it
+// without locking.
+type levelTable struct {
+	custom []customLevel // indexed by firstCustomLevel - level
+}
+
+var (
+	levels      atomic.Pointer[levelTable]
+	levelsMutex sync.Mutex
+)
+
+// loadLevels returns the current table; it is only empty before package
+// initialization has registered NoticeLevel.
+func loadLevels() *levelTable {
+	if t := levels.Load(); t != nil {
+		return t
+	}
+	return &levelTable{}
+}
+
+// RegisterLevel adds a level named name (lower case, e.g. "notice") that
+// sorts directly above the built-in level above, after any level registered
+// above it before.
+func RegisterLevel(name string, above zapcore.Level) zapcore.Level {
+	if above < zapcore.DebugLevel || above > zapcore.FatalLevel {
+		panic(fmt.Sprintf("logger: RegisterLevel(%q): %v is not a built-in level", name, above))
+	}
+	name = strings.ToLower(name)
+
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+
+	if _, err := ParseLevel(name); err == nil {
+		panic(fmt.Sprintf("logger: RegisterLevel(%q): already registered", name))
+	}
+	old := loadLevels()
+	if int(firstCustomLevel)-len(old.custom) <= -128 {
+		panic(fmt.Sprintf("logger: RegisterLevel(%q): too many levels", name))
+	}
+	severity := builtinSeverity(above) + 1
+	for _, c := range old.custom {
+		if c.severity >= severity && c.severity < builtinSeverity(above+1) {
+			severity = c.severity + 1
+		}
+	}
+	t := &levelTable{custom: append(old.custom[:len(old.custom):len(old.custom)], customLevel{name, severity})}
+	levels.Store(t)
+	return firstCustomLevel - zapcore.Level(len(t.custom)-1)
+}
+
+// builtinSeverity spaces zap's levels 16 apart to leave room for registered
+// levels in between; TraceLevel sits halfway below debug.
+func builtinSeverity(level zapcore.Level) int {
+	return (int(level) + 2) * 16
+}
+
+// levelSeverity orders built-in and registered levels.
+func levelSeverity(level zapcore.Level) int {
+	if level >= zapcore.DebugLevel {
+		return builtinSeverity(level)
+	}
+	if level == TraceLevel {
+		return builtinSeverity(zapcore.DebugLevel) / 2
+	}
+	if t := loadLevels(); int(firstCustomLevel-level) < len(t.custom) {
+		return t.custom[firstCustomLevel-level].severity
+	}
+	return 0
+}
+
+// levelName returns the lower-case level name used by themes and ParseLevel.
+func levelName(level zapcore.Level) string {
+	if level >= zapcore.DebugLevel {
+		return level.String()
+	}
+	if level == TraceLevel {
+		return "trace"
+	}
+	if t := loadLevels(); int(firstCustomLevel-level) < len(t.custom) {
+		return t.custom[firstCustomLevel-level].name
+	}
+	return level.String()
+}
+
+// ParseLevel converts a built-in or registered level name into a zapcore.Level.
+func ParseLevel(s string) (zapcore.Level, error) {
+	name := strings.ToLower(s)
+	if name == "trace" {
+		return TraceLevel, nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(name)); err == nil && name != "" {
+		return level, nil
+	}
+	for i, c := range loadLevels().custom {
+		if c.name == name {
+			return firstCustomLevel - zapcore.Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("logger: unknown level %q", s)
+}
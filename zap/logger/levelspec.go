@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Per-Component Levels
+// -------------------------------------------------------------
+
+// LevelSpec sets per-component levels, parsed from a spec such as
+// "PFCP=debug,SESS=debug,*=info". Exact component names win over patterns,
+// patterns (path.Match syntax, e.g. "S*" or "GS?") apply in the order given,
+// and "*" sets the level of every other component. Components matching
+// nothing follow the global level.
+type LevelSpec struct {
+	exact    map[string]zapcore.Level
+	patterns []levelRule
+	fallback *zapcore.Level // "*"
+	spec     string
+}
+
+// levelRule is one "pattern=level" entry.
+type levelRule struct {
+	pattern string
+	level   zapcore.Level
+}
+
+// ParseLevelSpec parses a comma-separated list of component=level entries.
+// Levels may be built-in or registered; an empty spec clears all overrides.
+func ParseLevelSpec(spec string) (*LevelSpec, error) {
+	s := &LevelSpec{exact: make(map[string]zapcore.Level), spec: spec}
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, levelName, ok := strings.Cut(entry, "=")
+		name, levelName = strings.TrimSpace(name), strings.TrimSpace(levelName)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("logger: level spec entry %q: want component=level", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("logger: level spec entry %q: %s is listed twice", entry, name)
+		}
+		seen[name] = true
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("logger: level spec entry %q: unknown level %q", entry, levelName)
+		}
+		switch {
+		case name == "*":
+			s.fallback = &level
+		case strings.ContainsAny(name, "*?[\\"):
+			if _, err := path.Match(name, ""); err != nil {
+				return nil, fmt.Errorf("logger: level spec entry %q: bad pattern %q", entry, name)
+			}
+			s.patterns = append(s.patterns, levelRule{name, level})
+		default:
+			s.exact[name] = level
+		}
+	}
+	return s, nil
+}
+
+// String returns the spec as parsed.
+func (s *LevelSpec) String() string {
+	return s.spec
+}
+
+// levelFor returns the level of component, or def if the spec has none.
+func (s *LevelSpec) levelFor(component string, def zapcore.Level) zapcore.Level {
+	if s == nil {
+		return def
+	}
+	if level, ok := s.exact[component]; ok {
+		return level
+	}
+	for _, r := range s.patterns {
+		if ok, _ := path.Match(r.pattern, component); ok {
+			return r.level
+		}
+	}
+	if s.fallback != nil {
+		return *s.fallback
+	}
+	return def
+}
+
+var (
+	// levelStateMutex guards the global level, the component spec and the
+	// per-component levels they are applied to.
+	levelStateMutex sync.Mutex
+	defaultLevel    zapcore.Level
+	componentSpec   *LevelSpec
+	componentLevels = make(map[string]*atomicLevel)
+	componentLimits = make(map[string]*atomic.Pointer[rateBucket])
+)
+
+// setLevels changes the global level and, if spec is not nil, the
+// per-component levels.
+func setLevels(logLevel zapcore.Level, spec *LevelSpec) {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	defaultLevel = logLevel
+	if spec != nil {
+		componentSpec = spec
+	}
+	applyLevels()
+}
+
+// SetComponentLevels replaces the per-component levels with spec, e.g.
+// "PFCP=debug,SESS=debug,*=info". An empty spec clears them.
+func SetComponentLevels(spec string) error {
+	s, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	componentSpec = s
+	applyLevels()
+	return nil
+}
+
+// ComponentLevels returns the current per-component level spec.
+func ComponentLevels() string {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	return componentSpec.String()
+}
+
+// applyLevels pushes the global level and component spec to every level;
+// the caller must hold levelStateMutex.
+func applyLevels() {
+	level.set(defaultLevel)
+	for name, l := range componentLevels {
+		l.set(componentSpec.levelFor(name, defaultLevel))
+	}
+}
+
+// ComponentLogger is the logger of one component: a zap.SugaredLogger, whose
+// methods it has, plus Ctx for the fields of a context.Context.
+type ComponentLogger struct {
+	*zap.SugaredLogger
+}
+
+// Sugared is the logger taken by RecoverAndLog, LineWriter and the like: a
+// *ComponentLogger, or any *zap.SugaredLogger such as one from With.
+type Sugared interface {
+	Desugar() *zap.Logger
+}
+
+// NewComponentLogger returns a logger named name whose level follows the
+// per-component levels. Its core checks that level before the sugared
+// methods format anything, so filtered calls stay cheap. Initialize must
+// have been called.
+func NewComponentLogger(name string) *ComponentLogger {
+	levelStateMutex.Lock()
+	l, ok := componentLevels[name]
+	if !ok {
+		l = new(atomicLevel)
+		l.set(componentSpec.levelFor(name, defaultLevel))
+		componentLevels[name] = l
+	}
+	limit, ok := componentLimits[name]
+	if !ok {
+		limit = new(atomic.Pointer[rateBucket])
+		applyRateLimit(name, limit)
+		componentLimits[name] = limit
+	}
+	levelStateMutex.Unlock()
+
+	core := &swapCore{level: l, current: &currentCore, limit: limit}
+	return &ComponentLogger{Logger().WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return core
+	})).Sugar().Named(name)}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetComponentLevels(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+	if err := SetComponentLevels("PFCP=debug,S*=warn"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ComponentLevels(); got != "PFCP=debug,S*=warn" {
+		t.Errorf("ComponentLevels = %q", got)
+	}
+
+	PfcpLog.Debug("pfcp debug")
+	SBILog.Info("sbi info")
+	SBILog.Warn("sbi warn")
+	GsmLog.Debug("gsm debug")
+	GsmLog.Info("gsm info")
+	NewComponentLogger("SMF").Info("created after the spec")
+
+	// the global level moves the components without a level of their own
+	SetLevel(zapcore.DebugLevel)
+	SBILog.Info("sbi info again")
+	GsmLog.Debug("gsm debug on")
+
+	// Reconfigure keeps the spec unless given one
+	if err := Reconfigure(zapcore.ErrorLevel, WithColor(ColorNever), WithWriter(out)); err != nil {
+		t.Fatal(err)
+	}
+	PfcpLog.Debug("pfcp kept")
+	GsmLog.Warn("gsm filtered")
+	if err := Reconfigure(zapcore.ErrorLevel, WithColor(ColorNever), WithWriter(out), WithComponentLevels("")); err != nil {
+		t.Fatal(err)
+	}
+	PfcpLog.Warn("pfcp cleared")
+
+	checkLines(t, out.String(),
+		"| DEBUG | PFCP  | pfcp debug",
+		"| WARN  | SBI   | sbi warn",
+		"| INFO  | GSM   | gsm info",
+		"| DEBUG | GSM   | gsm debug on",
+		"| DEBUG | PFCP  | pfcp kept",
+	)
+
+	if err := SetComponentLevels("PFCP=loud"); err == nil {
+		t.Error("unknown level accepted")
+	}
+	if ComponentLevels() != "" {
+		t.Errorf("a failed SetComponentLevels changed the spec to %q", ComponentLevels())
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCloseKeepsWrappers(t *testing.T) {
+	stderr := captureStderr(t)
+	useOutput(t, zapcore.InfoLevel, WithWriter(io.Discard), WithRedaction(Redaction{Mode: RedactMask}), WithDedup(time.Hour))
+	if err := Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		PduSessLog.Infow("late shutdown", "SUPI", "imsi-208930000000003")
+	}
+
+	got := stderr()
+	if strings.Contains(got, "208930000000003") || !strings.Contains(got, `late shutdown | {"SUPI": "imsi-***************"}`) {
+		t.Errorf("entry after Close not redacted:\n%s", got)
+	}
+	if n := strings.Count(got, "late shutdown"); n != 1 {
+		t.Errorf("entry after Close not deduplicated, %d copies:\n%s", n, got)
+	}
+}
+
+// captureStderr points os.Stderr at a file until the test ends; the
+// returned function reads what was written so far.
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() { os.Stderr = saved; f.Close() })
+	return func() string {
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
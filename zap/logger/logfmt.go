@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// logfmt Encoder (shared format with the phuslu bench/logger)
+// -------------------------------------------------------------
+
+// logfmt keys of the FormatLogfmt sinks. The phuslu bench/logger writes the
+// same line:
+//
+//	ts=2026-10-17T05:22:23.348Z level=info component=PFCP msg="Packet processed" seid=42
+//
+// "caller" follows the component with WithCaller; "stack" comes last when
+// present.
+const (
+	LogfmtTimeKey      = "ts"
+	LogfmtLevelKey     = "level"
+	LogfmtComponentKey = "component"
+	LogfmtCallerKey    = "caller"
+	LogfmtMessageKey   = "msg"
+	LogfmtStackKey     = "stack"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder is a zapcore.Encoder for the shared logfmt format. Arrays,
+// objects and reflected values are written as quoted JSON text; fields in a
+// namespace get "namespace." key prefixes.
+type logfmtEncoder struct {
+	time   zapcore.TimeEncoder
+	buf    *buffer.Buffer // context fields, each as " key=value"
+	prefix string         // open namespaces, e.g. "sess."
+}
+
+func newLogfmtEncoder(o options) zapcore.Encoder {
+	return &logfmtEncoder{
+		time: newTimeEncoder(TimeFormatRFC3339, o.utc),
+		buf:  logfmtPool.Get(),
+	}
+}
+
+// Clone implements zapcore.Encoder.
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{time: e.time, buf: logfmtPool.Get(), prefix: e.prefix}
+	clone.buf.Write(e.buf.Bytes())
+	return clone
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := logfmtPool.Get()
+	line.AppendString(LogfmtTimeKey + "=")
+	e.time(ent.Time, logfmtValue{line})
+	line.AppendString(" " + LogfmtLevelKey + "=")
+	line.AppendString(levelName(ent.Level))
+	if ent.LoggerName != "" {
+		line.AppendString(" " + LogfmtComponentKey + "=")
+		appendLogfmtString(line, ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		line.AppendString(" " + LogfmtCallerKey + "=")
+		appendLogfmtString(line, ent.Caller.TrimmedPath())
+	}
+	line.AppendString(" " + LogfmtMessageKey + "=")
+	appendLogfmtString(line, ent.Message)
+	line.Write(e.buf.Bytes())
+
+	fieldEnc := logfmtEncoder{buf: line, prefix: e.prefix}
+	for i := range fields {
+		fields[i].AddTo(&fieldEnc)
+	}
+	if ent.Stack != "" {
+		line.AppendString(" " + LogfmtStackKey + "=")
+		appendLogfmtString(line, ent.Stack)
+	}
+	line.AppendByte('\n')
+	return line, nil
+}
+
+// key starts the field key.
+func (e *logfmtEncoder) key(key string) {
+	e.buf.AppendByte(' ')
+	e.buf.AppendString(e.prefix)
+	appendLogfmtKey(e.buf, key)
+	e.buf.AppendByte('=')
+}
+
+// addJSON writes v as quoted JSON text.
+func (e *logfmtEncoder) addJSON(key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.key(key)
+	appendLogfmtString(e.buf, string(b))
+	return nil
+}
+
+// AddArray implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddArray(key, arr); err != nil {
+		return err
+	}
+	return e.addJSON(key, m.Fields[key])
+}
+
+// AddObject implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := obj.MarshalLogObject(m); err != nil {
+		return err
+	}
+	return e.addJSON(key, m.Fields)
+}
+
+// AddReflected implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddReflected(key string, v any) error { return e.addJSON(key, v) }
+
+// OpenNamespace implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	b := appendLogfmtKey(logfmtPool.Get(), key)
+	e.prefix += b.String() + "."
+	b.Free()
+}
+
+// AddBinary implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddBinary(key string, v []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(v))
+}
+
+// AddByteString implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddByteString(key string, v []byte) {
+	e.key(key)
+	logfmtValue{e.buf}.AppendByteString(v)
+}
+
+// AddBool implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddBool(key string, v bool) { e.key(key); e.buf.AppendBool(v) }
+
+// AddComplex128 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddComplex128(key string, v complex128) {
+	e.key(key)
+	logfmtValue{e.buf}.AppendComplex128(v)
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddComplex64(key string, v complex64) {
+	e.AddComplex128(key, complex128(v))
+}
+
+// AddDuration implements zapcore.ObjectEncoder, e.g. "1.5s".
+func (e *logfmtEncoder) AddDuration(key string, v time.Duration) {
+	e.key(key)
+	e.buf.AppendString(v.String())
+}
+
+// AddFloat64 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddFloat64(key string, v float64) {
+	e.key(key)
+	logfmtValue{e.buf}.AppendFloat64(v)
+}
+
+// AddFloat32 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddFloat32(key string, v float32) {
+	e.key(key)
+	logfmtValue{e.buf}.AppendFloat32(v)
+}
+
+// AddInt implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddInt(key string, v int) { e.AddInt64(key, int64(v)) }
+
+// AddInt64 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddInt64(key string, v int64) { e.key(key); e.buf.AppendInt(v) }
+
+// AddInt32 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddInt32(key string, v int32) { e.AddInt64(key, int64(v)) }
+
+// AddInt16 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddInt16(key string, v int16) { e.AddInt64(key, int64(v)) }
+
+// AddInt8 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddInt8(key string, v int8) { e.AddInt64(key, int64(v)) }
+
+// AddString implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddString(key, v string) { e.key(key); appendLogfmtString(e.buf, v) }
+
+// AddTime implements zapcore.ObjectEncoder, as RFC3339 with milliseconds.
+func (e *logfmtEncoder) AddTime(key string, v time.Time) {
+	e.key(key)
+	e.buf.AppendTime(v, TimeFormatRFC3339)
+}
+
+// AddUint implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUint(key string, v uint) { e.AddUint64(key, uint64(v)) }
+
+// AddUint64 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUint64(key string, v uint64) { e.key(key); e.buf.AppendUint(v) }
+
+// AddUint32 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUint32(key string, v uint32) { e.AddUint64(key, uint64(v)) }
+
+// AddUint16 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUint16(key string, v uint16) { e.AddUint64(key, uint64(v)) }
+
+// AddUint8 implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUint8(key string, v uint8) { e.AddUint64(key, uint64(v)) }
+
+// AddUintptr implements zapcore.ObjectEncoder.
+func (e *logfmtEncoder) AddUintptr(key string, v uintptr) { e.AddUint64(key, uint64(v)) }
+
+// logfmtValue writes single values for the time encoder and the
+// non-trivial field types.
+type logfmtValue struct{ buf *buffer.Buffer }
+
+func (v logfmtValue) AppendBool(b bool)           { v.buf.AppendBool(b) }
+func (v logfmtValue) AppendByteString(b []byte)   { appendLogfmtString(v.buf, string(b)) }
+func (v logfmtValue) AppendComplex64(c complex64) { v.AppendComplex128(complex128(c)) }
+func (v logfmtValue) AppendFloat32(f float32)     { v.appendFloat(float64(f), 32) }
+func (v logfmtValue) AppendFloat64(f float64)     { v.appendFloat(f, 64) }
+func (v logfmtValue) AppendInt(i int)             { v.buf.AppendInt(int64(i)) }
+func (v logfmtValue) AppendInt64(i int64)         { v.buf.AppendInt(i) }
+func (v logfmtValue) AppendInt32(i int32)         { v.buf.AppendInt(int64(i)) }
+func (v logfmtValue) AppendInt16(i int16)         { v.buf.AppendInt(int64(i)) }
+func (v logfmtValue) AppendInt8(i int8)           { v.buf.AppendInt(int64(i)) }
+func (v logfmtValue) AppendString(s string)       { appendLogfmtString(v.buf, s) }
+func (v logfmtValue) AppendUint(i uint)           { v.buf.AppendUint(uint64(i)) }
+func (v logfmtValue) AppendUint64(i uint64)       { v.buf.AppendUint(i) }
+func (v logfmtValue) AppendUint32(i uint32)       { v.buf.AppendUint(uint64(i)) }
+func (v logfmtValue) AppendUint16(i uint16)       { v.buf.AppendUint(uint64(i)) }
+func (v logfmtValue) AppendUint8(i uint8)         { v.buf.AppendUint(uint64(i)) }
+func (v logfmtValue) AppendUintptr(i uintptr)     { v.buf.AppendUint(uint64(i)) }
+func (v logfmtValue) AppendComplex128(c complex128) {
+	// e.g. "1+2i", quoted like zap's JSON encoder does
+	s := strconv.FormatFloat(real(c), 'g', -1, 64) + "+" + strconv.FormatFloat(imag(c), 'g', -1, 64) + "i"
+	appendLogfmtString(v.buf, s)
+}
+
+// appendFloat writes NaN and infinities like zap's JSON encoder.
+func (v logfmtValue) appendFloat(f float64, bits int) {
+	switch {
+	case math.IsNaN(f):
+		v.buf.AppendString("NaN")
+	case math.IsInf(f, 1):
+		v.buf.AppendString("+Inf")
+	case math.IsInf(f, -1):
+		v.buf.AppendString("-Inf")
+	default:
+		v.buf.AppendFloat(f, bits)
+	}
+}
+
+// appendLogfmtString appends s, quoted and escaped only if it needs it.
+func appendLogfmtString(buf *buffer.Buffer, s string) {
+	if needsQuote(s) {
+		// quote into the buffer's spare capacity; Write then copies in place
+		buf.Write(strconv.AppendQuote(buf.Bytes()[buf.Len():], s))
+		return
+	}
+	buf.AppendString(s)
+}
+
+// appendLogfmtKey appends key with the bytes logfmt does not allow in keys
+// (space, '=', '"' and control bytes) replaced by '_'.
+func appendLogfmtKey(buf *buffer.Buffer, key string) *buffer.Buffer {
+	if key == "" {
+		buf.AppendByte('_')
+		return buf
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			buf.AppendByte('_')
+		} else {
+			buf.AppendByte(c)
+		}
+	}
+	return buf
+}
+
+// needsQuote reports whether a string value must be quoted to stay
+// unambiguous in `key=value` form (empty, whitespace, quotes, '=' or control bytes).
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c == '"' || c == '=' || c == '\\' || c == 0x7f {
+			return true
+		}
+		if c >= utf8.RuneSelf {
+			// only quote non-ASCII text that is invalid or unprintable
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError || !unicode.IsPrint(r) {
+				return true
+			}
+			i += size - 1
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtParity(t *testing.T) {
+	ent := parityEntry(zapcore.InfoLevel)
+	ent.Time = time.Date(2026, 10, 17, 5, 22, 23, 348e6, time.UTC)
+	buf, err := newLogfmtEncoder(options{}).EncodeEntry(ent, []zapcore.Field{
+		zap.Int("seid", 42),
+		zap.Bool("ok", true),
+		zap.String("ue", `imsi "001"`),
+		zap.String("empty", ""),
+		zap.String("bad key=", "x"),
+		zap.Any("ids", []any{1, "a b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ts=2026-10-17T05:22:23.348Z level=info component=PFCP caller=smf/pfcp.go:42 msg="Packet processed" seid=42 ok=true ue="imsi \"001\"" empty="" bad_key_=x ids="[1,\"a b\"]"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
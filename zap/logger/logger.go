@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,90 +14,177 @@ import (
 // Global logger variables
 var (
 	globalLogger *zap.Logger
-	MainLog      *zap.SugaredLogger
-	NfLog        *zap.SugaredLogger
-	InitLog      *zap.SugaredLogger
-	CfgLog       *zap.SugaredLogger
-	CtxLog       *zap.SugaredLogger
-	GinLog       *zap.SugaredLogger
-	SBILog       *zap.SugaredLogger
-	ConsumerLog  *zap.SugaredLogger
-	GsmLog       *zap.SugaredLogger
-	PfcpLog      *zap.SugaredLogger
-	PduSessLog   *zap.SugaredLogger
-	ChargingLog  *zap.SugaredLogger
-	UtilLog      *zap.SugaredLogger
-	NwdafLog     *zap.SugaredLogger
+	MainLog      *ComponentLogger
+	NfLog        *ComponentLogger
+	InitLog      *ComponentLogger
+	CfgLog       *ComponentLogger
+	CtxLog       *ComponentLogger
+	GinLog       *ComponentLogger
+	SBILog       *ComponentLogger
+	ConsumerLog  *ComponentLogger
+	GsmLog       *ComponentLogger
+	PfcpLog      *ComponentLogger
+	PduSessLog   *ComponentLogger
+	ChargingLog  *ComponentLogger
+	UtilLog      *ComponentLogger
+	NwdafLog     *ComponentLogger
 )
 
-// customLevelEncoder formats log levels with colors and even spacing
-func customLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
-	var colorStart, colorEnd, levelStr string
-
-	switch level {
-	case zapcore.DebugLevel:
-		colorStart, colorEnd, levelStr = "\033[36m", "\033[0m", "DEBUG" // Cyan
-	case zapcore.InfoLevel:
-		colorStart, colorEnd, levelStr = "\033[32m", "\033[0m", "INFO " // Green
-	case zapcore.WarnLevel:
-		colorStart, colorEnd, levelStr = "\033[33m", "\033[0m", "WARN " // Yellow
-	case zapcore.ErrorLevel:
-		colorStart, colorEnd, levelStr = "\033[31m", "\033[0m", "ERR  " // Red
-	case zapcore.FatalLevel:
-		colorStart, colorEnd, levelStr = "\033[41m\033[37m", "\033[0m", "FATAL" // White on Red
-	default:
-		colorStart, colorEnd, levelStr = "", "", level.CapitalString()
-	}
-
-	enc.AppendString(colorStart + levelStr + colorEnd)
-}
-
 // customComponentEncoder formats the component field for logs
 func customComponentEncoder(loggerName string, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(fmt.Sprintf("%-5s", fmt.Sprintf("%s", loggerName)))
 }
 
-// Initialize initializes the global logger and component loggers
-func Initialize(logLevel zapcore.Level) {
+// Runtime state behind every logger from Initialize
+var (
+	level       atomicLevel
+	currentCore atomic.Pointer[coreBox]
+
+	// configMutex serializes Initialize and Reconfigure; the caller options
+	// are those of the first call.
+	configMutex sync.Mutex
+	caller      bool
+	callerSkip  int
+
+	// current holds the options of the last configure, for Close.
+	current options
+)
+
+// Initialize initializes the global logger and component loggers. logLevel
+// may be a built-in level, TraceLevel or one returned by RegisterLevel.
+// The LOG_* environment variables (see EnvLevel) override logLevel and opts.
+// Later calls reconfigure the running loggers like Reconfigure, except that
+// the caller options keep their first value. It panics on an invalid option,
+// such as a malformed WithComponentLevels spec. Invalid LOG_* variables, and
+// LOG_OUTPUT files that cannot be opened, are reported on stderr and ignored.
+func Initialize(logLevel zapcore.Level, opts ...Option) {
+	logLevel, envOpts, files, err := envOptions(logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\nlogger: ignoring these LOG_* settings\n", err)
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	o := buildOptions(append(opts[:len(opts):len(opts)], envOpts...))
+	if o.err != nil {
+		closeFiles(files)
+		panic(o.err)
+	}
+	configure(logLevel, o)
+	if files != nil {
+		replaceConfigFiles(files)
+	}
+}
+
+// configure applies o, taking the caller options only on the first call; the
+// caller must hold configMutex.
+func configure(logLevel zapcore.Level, o options) {
+	applyOutputs(o)
+	current = o
+	setLevels(logLevel, o.componentLevels)
+	if o.rateLimits != nil {
+		setRateLimits(o.rateLimits)
+	}
 	if globalLogger != nil {
 		return
 	}
 
-	customEncoder := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
-		TimeKey:          "timestamp",
-		LevelKey:         "level",
-		CallerKey:        "caller", // Shows file:line
-		MessageKey:       "message",
-		EncodeTime:       zapcore.TimeEncoderOfLayout("2006-01-02 | 15:04:05.000"),
-		EncodeLevel:      customLevelEncoder,         // Add colors to log levels
-		EncodeCaller:     zapcore.ShortCallerEncoder, // Shows file:line
-		NameKey:          "component",
-		EncodeName:       customComponentEncoder, // Add component field inline
-		ConsoleSeparator: " | ",
-	})
-
-	output := zapcore.Lock(os.Stdout)
-	core := zapcore.NewCore(customEncoder, output, logLevel)
+	zapOpts := []zap.Option{zap.WithFatalHook(exitHook{})} // flush before os.Exit
+	if o.caller {
+		zapOpts = append(zapOpts, callerOptions(o.callerSkip)...)
+	}
+	caller, callerSkip = o.caller, o.callerSkip
 
-	globalLogger = zap.New(core)
-	sugaredLogger := globalLogger.Sugar()
+	globalLogger = zap.New(&swapCore{level: &level, current: &currentCore}, zapOpts...)
 
 	// Initialize component loggers
-	MainLog = sugaredLogger.Named("MAIN")
-	NfLog = sugaredLogger.Named("NF")
-	InitLog = sugaredLogger.Named("INIT")
-	CfgLog = sugaredLogger.Named("CFG")
-	CtxLog = sugaredLogger.Named("CTX")
-	GinLog = sugaredLogger.Named("GIN")
-	SBILog = sugaredLogger.Named("SBI")
-	ConsumerLog = sugaredLogger.Named("CONS")
-	GsmLog = sugaredLogger.Named("GSM")
-	PfcpLog = sugaredLogger.Named("PFCP")
-	PduSessLog = sugaredLogger.Named("SESS")
-	ChargingLog = sugaredLogger.Named("CHARGE")
-	UtilLog = sugaredLogger.Named("UTIL")
-	NwdafLog = sugaredLogger.Named("NWDAF")
-	PduSessLog = sugaredLogger.Named("SESS")
+	MainLog = NewComponentLogger("MAIN")
+	NfLog = NewComponentLogger("NF")
+	InitLog = NewComponentLogger("INIT")
+	CfgLog = NewComponentLogger("CFG")
+	CtxLog = NewComponentLogger("CTX")
+	GinLog = NewComponentLogger("GIN")
+	SBILog = NewComponentLogger("SBI")
+	ConsumerLog = NewComponentLogger("CONS")
+	GsmLog = NewComponentLogger("GSM")
+	PfcpLog = NewComponentLogger("PFCP")
+	PduSessLog = NewComponentLogger("SESS")
+	ChargingLog = NewComponentLogger("CHARGE")
+	UtilLog = NewComponentLogger("UTIL")
+	NwdafLog = NewComponentLogger("NWDAF")
+	PduSessLog = NewComponentLogger("SESS")
+}
+
+// applyOutputs replaces the core with that of o: its sinks behind
+// redaction, sampling and dedup. The caller must hold configMutex.
+func applyOutputs(o options) {
+	core := newSinkCore(o)
+	if o.redact != nil {
+		core = &redactCore{Core: core, r: o.redact}
+	}
+	direct := core
+	if o.sampling != nil {
+		core = newSamplingCore(*o.sampling, core)
+	}
+	old := activeDeduper
+	activeDeduper = nil
+	if o.dedup > 0 {
+		activeDeduper = newDeduper(o.dedup)
+		core = &dedupCore{Core: core, d: activeDeduper}
+	}
+	setCore(core, direct)
+	if old != nil {
+		old.flush() // pending repeats go to the outputs they were logged to
+	}
+}
+
+// Reconfigure atomically swaps the level, output, encoder, theme and
+// timestamp settings of the running loggers, including every component
+// logger and every logger derived from them. Per-component levels are kept
+// unless WithComponentLevels is given. It initializes the loggers if needed.
+// The caller options are fixed by the first configuration; changing them is
+// an error.
+func Reconfigure(logLevel zapcore.Level, opts ...Option) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	o := buildOptions(opts)
+	if o.err != nil {
+		return o.err
+	}
+	if globalLogger != nil && (o.caller != caller || caller && o.callerSkip != callerSkip) {
+		return errors.New("logger: caller options cannot change after Initialize")
+	}
+	configure(logLevel, o)
+	return nil
+}
+
+// callerOnReload returns the caller options for a reload that asks for
+// caller output to be on or off. Once the logger is configured they keep the
+// running settings, and ignored reports whether want had to be dropped.
+func callerOnReload(want bool) (opts []Option, ignored bool) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	on, skip := want, 0
+	if globalLogger != nil {
+		on, skip = caller, callerSkip
+	}
+	if on {
+		opts = []Option{WithCaller(), WithCallerSkip(skip)}
+	}
+	return opts, on != want
+}
+
+// SetLevel changes the level of the global logger and of every component
+// logger without a per-component level.
+func SetLevel(logLevel zapcore.Level) {
+	setLevels(logLevel, nil)
+}
+
+// GetLevel returns the level set by Initialize, Reconfigure or SetLevel.
+func GetLevel() zapcore.Level {
+	return level.get()
 }
 
 // Logger provides access to the global structured logger
@@ -111,4 +201,4 @@ func Sugar() *zap.SugaredLogger {
 		panic("Logger is not initialized. Call Initialize first.")
 	}
 	return globalLogger.Sugar()
-}
\ No newline at end of file
+}
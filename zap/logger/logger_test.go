@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syncBuffer is a bytes.Buffer that loggers may write to concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// useOutput reconfigures the loggers to write plain console lines at level,
+// with opts, to the returned buffer until the test ends.
+func useOutput(t testing.TB, level zapcore.Level, opts ...Option) *syncBuffer {
+	t.Helper()
+	out := new(syncBuffer)
+	if err := Reconfigure(level, append([]Option{WithColor(ColorNever), WithWriter(out)}, opts...)...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reconfigure(zapcore.InfoLevel, WithWriter(io.Discard), WithComponentLevels("")) })
+	return out
+}
+
+// checkLines reports the lines of got not ending in the corresponding want.
+func checkLines(t *testing.T, got string, want ...string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), got)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+	derived := PfcpLog.With("seid", 42)
+
+	PfcpLog.Debug("filtered")
+	SetLevel(zapcore.DebugLevel)
+	if GetLevel() != zapcore.DebugLevel {
+		t.Errorf("GetLevel = %v after SetLevel(debug)", GetLevel())
+	}
+	PfcpLog.Debug("debug on")
+	derived.Debug("derived follows")
+	SetLevel(NoticeLevel)
+	PfcpLog.Info("below notice")
+	PfcpLog.Log(NoticeLevel, "notice")
+	SetLevel(TraceLevel)
+	PfcpLog.Log(TraceLevel, "trace")
+
+	// a new output and level reach loggers derived before the change
+	next := new(syncBuffer)
+	if err := Reconfigure(zapcore.WarnLevel, WithColor(ColorNever), WithWriter(next)); err != nil {
+		t.Fatal(err)
+	}
+	derived.Info("filtered again")
+	derived.Warn("moved")
+
+	checkLines(t, out.String(),
+		"| DEBUG | PFCP  | debug on",
+		`| DEBUG | PFCP  | derived follows | {"seid": 42}`,
+		"| NOTE  | PFCP  | notice",
+		"| TRACE | PFCP  | trace",
+	)
+	checkLines(t, next.String(), `| WARN  | PFCP  | moved | {"seid": 42}`)
+
+	if err := Reconfigure(zapcore.InfoLevel, WithCaller()); err == nil {
+		t.Error("caller options changed after Initialize")
+	}
+	if err := Reconfigure(zapcore.InfoLevel, WithFormat("xml")); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
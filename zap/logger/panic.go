@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Panic Recovery and Fatal Exit
+// -------------------------------------------------------------
+
+// PanicPolicy is what RecoverAndLog does once a panic is logged and the
+// sinks are flushed.
+type PanicPolicy int
+
+const (
+	// PanicRepanic panics again with the same value, so the runtime or an
+	// outer recover still sees it. It is the default.
+	PanicRepanic PanicPolicy = iota
+	// PanicExit exits with status 2, as the runtime does for a panic.
+	PanicExit
+)
+
+// panicExitCode is the status of PanicExit.
+const panicExitCode = 2
+
+// ExitFlushTimeout bounds the flush that runs before a fatal entry or
+// RecoverAndLog ends the process, so a stuck sink cannot keep it alive.
+var ExitFlushTimeout = 5 * time.Second
+
+// WithPanicPolicy sets what RecoverAndLog does after logging; see
+// PanicPolicy.
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(o *options) {
+		if p != PanicRepanic && p != PanicExit {
+			o.err = fmt.Errorf("logger: unknown panic policy %d", p)
+			return
+		}
+		o.panicPolicy = p
+	}
+}
+
+// RecoverAndLog, deferred at the top of a goroutine, logs a panic at fatal
+// level with the component's tag, the panic value and the full goroutine
+// stack, flushes every sink, and then re-panics or exits according to the
+// panic policy:
+//
+//	go func() {
+//		defer logger.RecoverAndLog(logger.PfcpLog)
+//		...
+//	}()
+//
+// It must be deferred directly; it does nothing if there is no panic.
+func RecoverAndLog(l Sugared) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	logPanic(l, v, debug.Stack())
+	flushForExit()
+
+	configMutex.Lock()
+	policy := current.panicPolicy
+	configMutex.Unlock()
+	if policy == PanicExit {
+		os.Exit(panicExitCode)
+	}
+	panic(v)
+}
+
+// logPanic writes the entry of RecoverAndLog straight to the logger's core,
+// because zap.Logger.Fatal would exit after writing it. Being fatal, it
+// passes rate limits, sampling and dedup.
+func logPanic(l Sugared, v any, stack []byte) {
+	zl := Logger()
+	if l != nil {
+		zl = l.Desugar()
+	}
+	ent := zapcore.Entry{
+		Level:      zapcore.FatalLevel,
+		Time:       time.Now(),
+		LoggerName: zl.Name(),
+		Message:    fmt.Sprint("panic: ", v),
+		Stack:      string(stack),
+	}
+	ce := zl.Core().Check(ent, nil)
+	if ce == nil {
+		return
+	}
+	if err, ok := v.(error); ok {
+		ce.Write(Err(err))
+	} else {
+		ce.Write()
+	}
+}
+
+// exitHook is the fatal hook of every logger: it flushes the sinks before
+// exiting with status 1, as zap does.
+type exitHook struct{}
+
+// OnWrite implements zapcore.CheckWriteHook.
+func (exitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	flushForExit()
+	os.Exit(1)
+}
+
+// flushForExit runs Flush within ExitFlushTimeout before the process ends,
+// reporting what could not be written on stderr.
+func flushForExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), ExitFlushTimeout)
+	defer cancel()
+	if err := Flush(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
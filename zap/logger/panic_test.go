@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	errBoom := errors.New("boom")
+	repanicked := make(chan any)
+	go func() {
+		defer func() { repanicked <- recover() }()
+		defer RecoverAndLog(NewComponentLogger("TASK"))
+		panic(errBoom)
+	}()
+	if v := <-repanicked; v != errBoom {
+		t.Fatalf("re-panicked with %v, want the original value", v)
+	}
+
+	got := out.String()
+	lines := strings.Split(got, "\n")
+	if !strings.HasSuffix(lines[0], `| FATAL | TASK  | panic: boom | {"error": "boom"}`) {
+		t.Errorf("entry line = %q", lines[0])
+	}
+	if !strings.Contains(got, "goroutine ") || !strings.Contains(got, "logger.TestRecoverAndLog.func") {
+		t.Errorf("goroutine stack missing:\n%s", got)
+	}
+
+	// no panic, nothing logged
+	func() { defer RecoverAndLog(NewComponentLogger("TASK")) }()
+	if out.String() != got {
+		t.Error("RecoverAndLog logged without a panic")
+	}
+}
+
+// TestRecoverAndLogExit runs itself in a child process, which panics under
+// PanicExit. A re-panic would exit with status 3 instead, as the runtime's
+// own status for a panic is that of PanicExit.
+func TestRecoverAndLogExit(t *testing.T) {
+	if os.Getenv("LOGGER_TEST_PANIC_EXIT") == "1" {
+		if err := Reconfigure(zapcore.InfoLevel, WithColor(ColorNever), WithWriter(os.Stdout), WithPanicPolicy(PanicExit)); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if recover() != nil {
+				os.Exit(3)
+			}
+		}()
+		defer RecoverAndLog(MainLog)
+		panic("boom")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRecoverAndLogExit$")
+	cmd.Env = append(os.Environ(), "LOGGER_TEST_PANIC_EXIT=1")
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != panicExitCode {
+		t.Fatalf("child exited with %v, want status %d:\n%s", err, panicExitCode, out)
+	}
+	if !strings.Contains(string(out), "| FATAL | MAIN  | panic: boom\n") {
+		t.Errorf("panic entry missing:\n%s", out)
+	}
+}
+
+func TestWithPanicPolicy(t *testing.T) {
+	if o := buildOptions([]Option{WithPanicPolicy(PanicExit)}); o.err != nil || o.panicPolicy != PanicExit {
+		t.Errorf("policy %d, err %v", o.panicPolicy, o.err)
+	}
+	if o := buildOptions([]Option{WithPanicPolicy(7)}); o.err == nil {
+		t.Error("unknown policy accepted")
+	}
+}
@@ -0,0 +1,315 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Per-Component Rate Limits
+// -------------------------------------------------------------
+
+// RateLimitReportInterval is how often components that dropped entries
+// report it, as a "component X dropped N entries" warning.
+const RateLimitReportInterval = 10 * time.Second
+
+// RateLimitSpec sets per-component rate limits, parsed from a spec such as
+// "GIN=1000/s,NWDAF=200/s". Rates are per second, minute or hour ("/s", "/m"
+// or "/h"), and a full bucket lets one period's worth through at once.
+// Components are matched like in LevelSpec: exact names, then patterns in
+// the order given, then "*". DPanic, panic and fatal entries are never
+// limited.
+type RateLimitSpec struct {
+	exact    map[string]rateLimit
+	patterns []rateRule
+	fallback *rateLimit // "*"
+	spec     string
+}
+
+// rateLimit is n entries per period.
+type rateLimit struct {
+	n   int64
+	per time.Duration
+}
+
+// rateRule is one "pattern=rate" entry.
+type rateRule struct {
+	pattern string
+	limit   rateLimit
+}
+
+// rateUnits are the periods a rate may be given per.
+var rateUnits = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+// ParseRateLimitSpec parses a comma-separated list of component=rate
+// entries; an empty spec removes all limits.
+func ParseRateLimitSpec(spec string) (*RateLimitSpec, error) {
+	s := &RateLimitSpec{exact: make(map[string]rateLimit), spec: spec}
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rate, ok := strings.Cut(entry, "=")
+		name, rate = strings.TrimSpace(name), strings.TrimSpace(rate)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("logger: rate limit entry %q: want component=rate, e.g. GIN=1000/s", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("logger: rate limit entry %q: %s is listed twice", entry, name)
+		}
+		seen[name] = true
+		n, unit, _ := strings.Cut(rate, "/")
+		count, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+		per, ok := rateUnits[strings.TrimSpace(unit)]
+		if err != nil || count < 0 || !ok {
+			return nil, fmt.Errorf("logger: rate limit entry %q: bad rate %q, want N/s, N/m or N/h", entry, rate)
+		}
+		limit := rateLimit{n: count, per: per}
+		switch {
+		case name == "*":
+			s.fallback = &limit
+		case strings.ContainsAny(name, "*?[\\"):
+			if _, err := path.Match(name, ""); err != nil {
+				return nil, fmt.Errorf("logger: rate limit entry %q: bad pattern %q", entry, name)
+			}
+			s.patterns = append(s.patterns, rateRule{name, limit})
+		default:
+			s.exact[name] = limit
+		}
+	}
+	return s, nil
+}
+
+// String returns the spec as parsed.
+func (s *RateLimitSpec) String() string {
+	return s.spec
+}
+
+// limitFor returns the limit of component, if it has one.
+func (s *RateLimitSpec) limitFor(component string) (rateLimit, bool) {
+	if s == nil {
+		return rateLimit{}, false
+	}
+	if l, ok := s.exact[component]; ok {
+		return l, true
+	}
+	for _, r := range s.patterns {
+		if ok, _ := path.Match(r.pattern, component); ok {
+			return r.limit, true
+		}
+	}
+	if s.fallback != nil {
+		return *s.fallback, true
+	}
+	return rateLimit{}, false
+}
+
+func (l rateLimit) String() string {
+	for unit, per := range rateUnits {
+		if per == l.per {
+			return fmt.Sprintf("%d/%s", l.n, unit)
+		}
+	}
+	return fmt.Sprintf("%d/%s", l.n, l.per)
+}
+
+// rateBucket is the token bucket of one component, kept as the theoretical
+// arrival time of the next entry (GCRA) so that taking a token is a single
+// compare-and-swap.
+type rateBucket struct {
+	component string
+	limit     rateLimit
+	interval  int64 // nanoseconds per token
+	tolerance int64 // how far ahead of now the arrival time may run: the burst
+
+	tat      atomic.Int64
+	allowed  atomic.Uint64
+	dropped  atomic.Uint64
+	reported atomic.Uint64 // dropped at the last report
+}
+
+func newRateBucket(component string, l rateLimit) *rateBucket {
+	b := &rateBucket{component: component, limit: l}
+	if l.n > 0 {
+		b.interval = int64(l.per) / l.n
+		b.tolerance = int64(l.per) - b.interval
+	}
+	return b
+}
+
+// allow takes a token at now, in unix nanoseconds, and counts the outcome.
+func (b *rateBucket) allow(now int64) bool {
+	if b.limit.n > 0 {
+		for {
+			tat := b.tat.Load()
+			next := max(tat, now)
+			if next-now > b.tolerance {
+				break
+			}
+			if b.tat.CompareAndSwap(tat, next+b.interval) {
+				b.allowed.Add(1)
+				return true
+			}
+		}
+	}
+	b.dropped.Add(1)
+	return false
+}
+
+// unreported returns the entries dropped since the last call.
+func (b *rateBucket) unreported() uint64 {
+	dropped := b.dropped.Load()
+	return dropped - b.reported.Swap(dropped)
+}
+
+// RateLimitStats counts the entries of one rate-limited component.
+type RateLimitStats struct {
+	Limit   string // e.g. "1000/s"
+	Allowed uint64
+	Dropped uint64
+}
+
+// GetRateLimitStats returns the counters of every rate-limited component
+// since its limit was last set.
+func GetRateLimitStats() map[string]RateLimitStats {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	stats := make(map[string]RateLimitStats, len(rateBuckets))
+	for name, b := range rateBuckets {
+		stats[name] = RateLimitStats{Limit: b.limit.String(), Allowed: b.allowed.Load(), Dropped: b.dropped.Load()}
+	}
+	return stats
+}
+
+// rate limit state, guarded by levelStateMutex like the levels.
+var (
+	rateLimitSpec *RateLimitSpec
+	rateBuckets   map[string]*rateBucket
+	stopReports   chan struct{} // closes the report loop, nil if not running
+)
+
+// WithRateLimits sets per-component rate limits from a spec such as
+// "GIN=1000/s,NWDAF=200/s"; see RateLimitSpec. Limits are unchanged if not
+// given.
+func WithRateLimits(spec string) Option {
+	return func(o *options) {
+		s, err := ParseRateLimitSpec(spec)
+		if err != nil {
+			if o.err == nil {
+				o.err = err
+			}
+			return
+		}
+		o.rateLimits = s
+	}
+}
+
+// SetRateLimits replaces the per-component rate limits with spec, e.g.
+// "GIN=1000/s,NWDAF=200/s". An empty spec removes them. Drops not yet
+// reported under the old limits are reported first.
+func SetRateLimits(spec string) error {
+	s, err := ParseRateLimitSpec(spec)
+	if err != nil {
+		return err
+	}
+	setRateLimits(s)
+	return nil
+}
+
+// RateLimits returns the current per-component rate limit spec.
+func RateLimits() string {
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	return rateLimitSpec.String()
+}
+
+func setRateLimits(s *RateLimitSpec) {
+	reportRateLimitDrops()
+	levelStateMutex.Lock()
+	defer levelStateMutex.Unlock()
+	rateLimitSpec = s
+	rateBuckets = make(map[string]*rateBucket)
+	for name, limit := range componentLimits {
+		applyRateLimit(name, limit)
+	}
+	switch {
+	case len(rateBuckets) > 0 && stopReports == nil:
+		stopReports = make(chan struct{})
+		go reportLoop(stopReports)
+	case len(rateBuckets) == 0 && stopReports != nil:
+		close(stopReports)
+		stopReports = nil
+	}
+}
+
+// applyRateLimit stores the bucket of component name in limit, which every
+// logger of that component shares; the caller must hold levelStateMutex.
+func applyRateLimit(name string, limit *atomic.Pointer[rateBucket]) {
+	l, ok := rateLimitSpec.limitFor(name)
+	if !ok {
+		limit.Store(nil)
+		return
+	}
+	b := rateBuckets[name]
+	if b == nil {
+		b = newRateBucket(name, l)
+		rateBuckets[name] = b
+	}
+	limit.Store(b)
+}
+
+// reportLoop reports drops every RateLimitReportInterval until stop closes.
+func reportLoop(stop <-chan struct{}) {
+	t := time.NewTicker(RateLimitReportInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			reportRateLimitDrops()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// reportRateLimitDrops logs a warning for each component that dropped
+// entries since its last report. It bypasses the component's own limit, the
+// logger levels, sampling and dedup, as the count is gone once taken.
+func reportRateLimitDrops() {
+	levelStateMutex.Lock()
+	buckets := make([]*rateBucket, 0, len(rateBuckets))
+	for _, b := range rateBuckets {
+		buckets = append(buckets, b)
+	}
+	levelStateMutex.Unlock()
+
+	for _, b := range buckets {
+		if n := b.unreported(); n > 0 {
+			writeDirect(zapcore.Entry{
+				Level:      zapcore.WarnLevel,
+				Time:       time.Now(),
+				LoggerName: b.component,
+				Message:    fmt.Sprintf("component %s dropped %d entries (rate limit %s)", b.component, n, b.limit),
+			})
+		}
+	}
+}
+
+// limited reports whether the component's bucket is empty. DPanic, panic
+// and fatal entries are never limited, as dropping them would skip the exit
+// or panic.
+func (c *swapCore) limited(ent zapcore.Entry) bool {
+	if c.limit == nil {
+		return false
+	}
+	b := c.limit.Load()
+	return b != nil && levelSeverity(ent.Level) < builtinSeverity(zapcore.DPanicLevel) && !b.allow(ent.Time.UnixNano())
+}
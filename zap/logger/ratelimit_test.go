@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseRateLimitSpec(t *testing.T) {
+	s, err := ParseRateLimitSpec("GIN=1000/s, NWDAF=200/m, S*=10/h, *=5000/s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for component, want := range map[string]string{"GIN": "1000/s", "NWDAF": "200/m", "SBI": "10/h", "PFCP": "5000/s"} {
+		if l, ok := s.limitFor(component); !ok || l.String() != want {
+			t.Errorf("limitFor(%s) = %v, %v; want %s", component, l, ok, want)
+		}
+	}
+	if _, ok := (&RateLimitSpec{}).limitFor("GIN"); ok {
+		t.Error("empty spec limits GIN")
+	}
+
+	for _, bad := range []string{"GIN", "GIN=1000", "GIN=fast/s", "GIN=-1/s", "GIN=10/d", "GIN=1/s,GIN=2/s", "[=1/s"} {
+		if _, err := ParseRateLimitSpec(bad); err == nil {
+			t.Errorf("ParseRateLimitSpec(%q) accepted", bad)
+		}
+	}
+}
+
+func TestRateBucket(t *testing.T) {
+	b := newRateBucket("GIN", rateLimit{n: 10, per: time.Second})
+	now := time.Now().UnixNano()
+	allowed := 0
+	for i := 0; i < 15; i++ {
+		if b.allow(now) {
+			allowed++
+		}
+	}
+	if allowed != 10 {
+		t.Errorf("full bucket let %d of 15 through, want 10", allowed)
+	}
+	if !b.allow(now+int64(100*time.Millisecond)) || b.allow(now+int64(100*time.Millisecond)) {
+		t.Error("bucket should refill one token per 100ms")
+	}
+	if b.allowed.Load() != 11 || b.dropped.Load() != 6 {
+		t.Errorf("allowed %d, dropped %d; want 11 and 6", b.allowed.Load(), b.dropped.Load())
+	}
+
+	if closed := newRateBucket("GIN", rateLimit{n: 0, per: time.Second}); closed.allow(now) {
+		t.Error("0/s let an entry through")
+	}
+}
+
+func TestComponentRateLimit(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithRateLimits("GIN=5/h"))
+	t.Cleanup(func() { _ = SetRateLimits("") })
+
+	for i := 0; i < 20; i++ {
+		GinLog.Infow("GET /nsmf-pdusession/v1/sm-contexts", "seq", i)
+	}
+	for i := 0; i < 3; i++ {
+		PfcpLog.Info("Association setup")
+	}
+	if gin, pfcp := strings.Count(out.String(), "| GIN "), strings.Count(out.String(), "| PFCP "); gin != 5 || pfcp != 3 {
+		t.Errorf("logged %d GIN and %d PFCP lines, want 5 and 3:\n%s", gin, pfcp, out.String())
+	}
+	if got, want := GetRateLimitStats()["GIN"], (RateLimitStats{Limit: "5/h", Allowed: 5, Dropped: 15}); got != want {
+		t.Errorf("GIN stats = %+v, want %+v", got, want)
+	}
+	if _, ok := GetRateLimitStats()["PFCP"]; ok {
+		t.Error("PFCP has no limit but has stats")
+	}
+
+	before := out.String()
+	reportRateLimitDrops()
+	reportRateLimitDrops()
+	if got := strings.TrimPrefix(out.String(), before); strings.Count(got, "\n") != 1 || !strings.Contains(got, "| WARN  | GIN   | component GIN dropped 15 entries (rate limit 5/h)") {
+		t.Errorf("drop report:\n%s", got)
+	}
+}
+
+func TestRateLimitReportUnfiltered(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithRateLimits("GIN=1/h"), WithSampling(Sampling{Tick: time.Hour, First: 1}))
+	t.Cleanup(func() { _ = SetRateLimits("") })
+
+	GinLog.Error("GET /nsmf-pdusession/v1/sm-contexts")
+	SetLevel(zapcore.ErrorLevel)
+	for i := 0; i < 2; i++ {
+		GinLog.Error("GET /nsmf-pdusession/v1/sm-contexts")
+		reportRateLimitDrops()
+	}
+	// both reports are below the level, and the second one would be sampled out
+	if got := out.String(); strings.Count(got, "component GIN dropped") != 2 {
+		t.Errorf("drop reports:\n%s", got)
+	}
+}
@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Sampling
+// -------------------------------------------------------------
+
+// Sampling thins out repeated entries. Within each Tick, the first First
+// entries with the same level and message are logged, then every
+// Thereafter-th. With Key set, entries are counted separately per value of
+// that field, e.g. "UE_ID", so one busy UE does not silence the others.
+// Fatal and panic entries are never sampled.
+//
+// Without Key this is zap's own sampler; registered levels outside zap's
+// range, such as TraceLevel, are then never sampled. Counters live in a
+// fixed table indexed by a hash of the key, so memory stays bounded; rare
+// collisions share a counter.
+type Sampling struct {
+	Tick       time.Duration // counting period, 1s if zero
+	First      int           // entries logged per key and tick before thinning
+	Thereafter int           // then log every Thereafter-th; 0 drops the rest
+	Key        string        // optional field name that splits the counters
+}
+
+// SamplingStats counts the entries seen by the sampler.
+type SamplingStats struct {
+	Sampled uint64 // logged
+	Dropped uint64 // discarded by the sampler
+}
+
+// samplingCounts are cumulative across Reconfigure.
+var samplingCounts struct {
+	sampled, dropped atomic.Uint64
+}
+
+// GetSamplingStats returns how many entries the sampler logged and dropped
+// since the program started.
+func GetSamplingStats() SamplingStats {
+	return SamplingStats{Sampled: samplingCounts.sampled.Load(), Dropped: samplingCounts.dropped.Load()}
+}
+
+// WithSampling enables sampling; see Sampling.
+func WithSampling(s Sampling) Option {
+	return func(o *options) {
+		o.sampling = &s
+	}
+}
+
+// sampleBuckets is the size of the counter table.
+const sampleBuckets = 8192
+
+// sampleCounter counts the entries of one key in the current tick.
+type sampleCounter struct {
+	resetAt atomic.Int64 // unix nanoseconds ending the tick
+	n       atomic.Uint64
+}
+
+// sampler is the counting shared by both backends.
+type sampler struct {
+	tick       int64
+	first      uint64
+	thereafter uint64
+	counters   [sampleBuckets]sampleCounter
+}
+
+func newSampler(s Sampling) *sampler {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &sampler{tick: int64(tick), first: uint64(max(s.First, 0)), thereafter: uint64(max(s.Thereafter, 0))}
+}
+
+// keep counts an entry with key hash h at now and reports whether it is
+// logged.
+func (s *sampler) keep(h uint64, now int64) bool {
+	n := s.counters[h%sampleBuckets].inc(now, s.tick)
+	if n <= s.first || s.thereafter != 0 && (n-s.first)%s.thereafter == 0 {
+		samplingCounts.sampled.Add(1)
+		return true
+	}
+	samplingCounts.dropped.Add(1)
+	return false
+}
+
+// inc counts one entry, starting a new tick if the last one has ended.
+func (c *sampleCounter) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if now < resetAt {
+		return c.n.Add(1)
+	}
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		return c.n.Add(1) // another goroutine started the tick
+	}
+	return 1
+}
+
+// FNV-1a, to hash sample keys without allocating.
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func fnvAdd[T string | []byte](h uint64, s T) uint64 {
+	for i := 0; i < len(s); i++ {
+		h = (h ^ uint64(s[i])) * fnvPrime
+	}
+	return h
+}
+
+// sampleHash hashes the level and message of an entry; the key field value
+// is added with fnvAdd.
+func sampleHash[T string | []byte](level int, msg T) uint64 {
+	h := (fnvOffset ^ uint64(level)) * fnvPrime
+	h = fnvAdd(h, msg)
+	return (h ^ 0xff) * fnvPrime // separates msg from the value
+}
+
+// newSamplingCore wraps core in zap's sampler, or in a keyedSampler when
+// the counters are split by a field, leaving out fatal and panic entries.
+func newSamplingCore(s Sampling, core zapcore.Core) zapcore.Core {
+	if s.Key == "" {
+		tick := s.Tick
+		if tick <= 0 {
+			tick = time.Second
+		}
+		sampled := zapcore.NewSamplerWithOptions(core, tick, max(s.First, 0), max(s.Thereafter, 0), zapcore.SamplerHook(countSample))
+		return &unsampledCore{Core: sampled, direct: core}
+	}
+	return &unsampledCore{Core: &keyedSampler{Core: core, s: newSampler(s), key: s.Key}, direct: core}
+}
+
+// unsampledCore sends fatal and panic entries straight to the core below
+// the sampler, so a crash is logged however busy the sampler is.
+type unsampledCore struct {
+	zapcore.Core              // the sampler
+	direct       zapcore.Core // the core it samples for
+}
+
+// With implements zapcore.Core.
+func (c *unsampledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &unsampledCore{Core: c.Core.With(fields), direct: c.direct.With(fields)}
+}
+
+// Check implements zapcore.Core.
+func (c *unsampledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.DPanicLevel && ent.Level <= zapcore.FatalLevel {
+		return c.direct.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// countSample feeds zap's sampler decisions into GetSamplingStats.
+func countSample(_ zapcore.Entry, d zapcore.SamplingDecision) {
+	if d&zapcore.LogDropped != 0 {
+		samplingCounts.dropped.Add(1)
+	} else {
+		samplingCounts.sampled.Add(1)
+	}
+}
+
+// keyedSampler samples by level, message and the value of one field, taken
+// from the entry or from With. It decides in Write, where the fields are.
+type keyedSampler struct {
+	zapcore.Core
+	s     *sampler
+	key   string
+	value *zapcore.Field // key field from With, if any
+}
+
+// With implements zapcore.Core.
+func (c *keyedSampler) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	for i := range fields {
+		if fields[i].Key == c.key {
+			clone.value = &fields[i]
+		}
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *keyedSampler) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *keyedSampler) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	h := sampleHash(int(ent.Level), ent.Message)
+	value := c.value
+	for i := range fields {
+		if fields[i].Key == c.key {
+			value = &fields[i]
+		}
+	}
+	if value != nil {
+		h = fieldHash(h, value)
+	}
+	if !c.s.keep(h, ent.Time.UnixNano()) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// fieldHash adds the value of f to h. Numbers, strings and durations hash
+// without allocating.
+func fieldHash(h uint64, f *zapcore.Field) uint64 {
+	if f.Interface != nil {
+		return fnvAdd(h, fmt.Sprint(f.Interface))
+	}
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(f.Integer))
+	return fnvAdd(fnvAdd(h, f.String), n[:])
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSampler(t *testing.T) {
+	s := newSampler(Sampling{Tick: time.Second, First: 2, Thereafter: 3})
+	h := sampleHash(int(zapcore.InfoLevel), "Packet processed successfully")
+	now := time.Now().UnixNano()
+
+	var kept []int
+	for n := 1; n <= 10; n++ {
+		if s.keep(h, now) {
+			kept = append(kept, n)
+		}
+	}
+	if got := fmt.Sprint(kept); got != "[1 2 5 8]" {
+		t.Errorf("kept entries %s, want [1 2 5 8]", got)
+	}
+	if !s.keep(h, now+int64(time.Second)) {
+		t.Error("first entry of the next tick was dropped")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithSampling(Sampling{Tick: time.Hour, First: 2, Thereafter: 5}))
+
+	before := GetSamplingStats()
+	for i := 0; i < 10; i++ {
+		PfcpLog.Infow("Packet processed successfully", "UE_ID", i)
+		PfcpLog.Warn("Packet processed successfully")
+	}
+	// per level and message: entries 1, 2 and 7 of 10
+	if lines := strings.Count(out.String(), "\n"); lines != 2*3 {
+		t.Errorf("logged %d lines, want 6:\n%s", lines, out.String())
+	}
+	after := GetSamplingStats()
+	if after.Sampled-before.Sampled != 6 || after.Dropped-before.Dropped != 14 {
+		t.Errorf("stats %+v -> %+v, want 6 sampled and 14 dropped", before, after)
+	}
+}
+
+func TestSamplingKey(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithSampling(Sampling{Tick: time.Hour, First: 2, Thereafter: 5, Key: "UE_ID"}))
+
+	ue := PfcpLog.With("UE_ID", 2001)
+	before := GetSamplingStats()
+	for i := 0; i < 10; i++ {
+		PfcpLog.Infow("Packet processed successfully", "UE_ID", 1001)
+		PfcpLog.Infow("Packet processed successfully", "UE_ID", 1002)
+		PfcpLog.Infow("Packet processed successfully", "UE_ID", "imsi-001", "seq", i)
+		PfcpLog.Warnw("Packet processed successfully", "UE_ID", 1001)
+		PfcpLog.Infow("Session established", "UE_ID", 1001)
+		ue.Info("Packet processed successfully")
+	}
+	// per key: entries 1, 2 and 7 of 10
+	if lines := strings.Count(out.String(), "\n"); lines != 6*3 {
+		t.Errorf("logged %d lines, want 18:\n%s", lines, out.String())
+	}
+	after := GetSamplingStats()
+	if after.Sampled-before.Sampled != 18 || after.Dropped-before.Dropped != 42 {
+		t.Errorf("stats %+v -> %+v, want 18 sampled and 42 dropped", before, after)
+	}
+}
+
+func TestSamplingKeepsFatal(t *testing.T) {
+	for _, key := range []string{"", "UE_ID"} {
+		out := useOutput(t, zapcore.InfoLevel, WithSampling(Sampling{Tick: time.Hour, First: 1, Thereafter: 0, Key: key}))
+		for i := 0; i < 3; i++ {
+			func() {
+				defer func() { _ = recover() }()
+				defer RecoverAndLog(PfcpLog)
+				panic("boom")
+			}()
+		}
+		if n := strings.Count(out.String(), "| FATAL | PFCP  | panic: boom\n"); n != 3 {
+			t.Errorf("key %q: %d of 3 panics logged:\n%s", key, n, out.String())
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Multi-Sink Fan-Out
+// -------------------------------------------------------------
+
+// Sink formats.
+const (
+	FormatConsole = "console" // "timestamp | level | component | message key=value", the default
+	FormatJSON    = "json"    // one JSON object per line
+	FormatLogfmt  = "logfmt"  // `ts=... level=info component=PFCP msg="..." key=value`
+)
+
+// sinkFormats are the valid Sink.Format values.
+var sinkFormats = map[string]bool{"": true, FormatConsole: true, FormatJSON: true, FormatLogfmt: true}
+
+// Sink is one log destination with its own format and minimum level.
+type Sink struct {
+	Writer io.Writer
+	Format string               // FormatConsole (default), FormatJSON or FormatLogfmt
+	Level  zapcore.LevelEnabler // minimum level, e.g. zapcore.ErrorLevel; nil takes every entry the logger passes
+	Color  ColorMode            // console colors; ColorAuto follows WithColor, then the terminal
+}
+
+// severityEnabler enables the levels at or above a severity, so registered
+// levels sort between the built-in ones.
+type severityEnabler int
+
+// Enabled implements zapcore.LevelEnabler.
+func (s severityEnabler) Enabled(level zapcore.Level) bool {
+	return levelSeverity(level) >= int(s)
+}
+
+// sinkEnabler returns the enabler of a Sink.Level, ordering levels by
+// severity; nil means every level.
+func sinkEnabler(e zapcore.LevelEnabler) zapcore.LevelEnabler {
+	if l, ok := e.(zapcore.Level); ok {
+		return severityEnabler(levelSeverity(l))
+	}
+	return e
+}
+
+// sinkOutput is a sink's writer and minimum level.
+type sinkOutput struct {
+	ws    zapcore.WriteSyncer
+	level zapcore.LevelEnabler // nil for every level
+}
+
+func (s sinkOutput) enabled(level zapcore.Level) bool {
+	return s.level == nil || s.level.Enabled(level)
+}
+
+// groupCore is every sink sharing one encoder: the entry is encoded once
+// and the buffer written to each output that wants its level.
+type groupCore struct {
+	enc     zapcore.Encoder
+	outputs []sinkOutput
+}
+
+// Enabled implements zapcore.Core.
+func (c *groupCore) Enabled(level zapcore.Level) bool {
+	for _, out := range c.outputs {
+		if out.enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// With implements zapcore.Core.
+func (c *groupCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+	return &groupCore{enc: enc, outputs: c.outputs}
+}
+
+// Check implements zapcore.Core.
+func (c *groupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *groupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	var errs []error
+	for _, out := range c.outputs {
+		if !out.enabled(ent.Level) {
+			continue
+		}
+		if _, err := out.ws.Write(buf.Bytes()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// like zap's ioCore: the process may be about to exit
+		errs = append(errs, c.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// Sync implements zapcore.Core.
+func (c *groupCore) Sync() error {
+	var errs []error
+	for _, out := range c.outputs {
+		errs = append(errs, out.ws.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// newSinkCore builds the core for o's sinks, grouping the sinks by their
+// encoding: format, and colors for the console format. It enables every
+// level the sinks take: logger filtering is done by the shared atomic levels.
+func newSinkCore(o options) zapcore.Core {
+	sinks := o.sinks
+	if len(sinks) == 0 {
+		sinks = []Sink{{Writer: os.Stdout}}
+	}
+
+	// one console sink taking everything: no fan-out needed
+	if len(sinks) == 1 && sinks[0].Level == nil && o.formatOf(sinks[0]) == FormatConsole {
+		return newConsoleSink(o, sinks[0].Writer, useColor(o.colorFor(sinks[0]), sinks[0].Writer))
+	}
+
+	type groupKey struct {
+		format string
+		color  bool
+	}
+	var cores []zapcore.Core
+	groups := make(map[groupKey]*groupCore)
+	for _, s := range sinks {
+		key := groupKey{format: o.formatOf(s)}
+		out := s.Writer
+		if key.format == FormatConsole {
+			key.color = useColor(o.colorFor(s), s.Writer)
+			if !key.color {
+				out = stripANSIWriter{out}
+			}
+		}
+
+		g := groups[key]
+		if g == nil {
+			g = &groupCore{}
+			switch key.format {
+			case FormatJSON:
+				g.enc = newJSONEncoder(o)
+			case FormatLogfmt:
+				g.enc = newLogfmtEncoder(o)
+			default:
+				g.enc = newConsoleEncoder(o, key.color)
+			}
+			groups[key] = g
+			cores = append(cores, g)
+		}
+		g.outputs = append(g.outputs, sinkOutput{
+			ws:    zapcore.Lock(zapcore.AddSync(out)),
+			level: sinkEnabler(s.Level),
+		})
+	}
+	if len(cores) == 1 {
+		return cores[0]
+	}
+	return zapcore.NewTee(cores...)
+}
+
+// JSON keys of the FormatJSON sinks. The phuslu bench/logger writes the same
+// keys in the same order, with the same level names and time layout:
+//
+//	{"level":"info","time":"2026-10-17T05:22:23.348Z","component":"PFCP","caller":"smf/pfcp.go:42","message":"Packet processed","seid":42}
+//
+// Fields follow the message; "stack" comes last when present.
+const (
+	JSONLevelKey     = "level"
+	JSONTimeKey      = "time"
+	JSONComponentKey = "component"
+	JSONCallerKey    = "caller"
+	JSONMessageKey   = "message"
+	JSONStackKey     = "stack"
+)
+
+// newJSONEncoder builds the encoder of the JSON format: RFC3339 times with
+// milliseconds and level names as ParseLevel spells them.
+func newJSONEncoder(o options) zapcore.Encoder {
+	return zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		LevelKey:      JSONLevelKey,
+		TimeKey:       JSONTimeKey,
+		NameKey:       JSONComponentKey,
+		CallerKey:     JSONCallerKey,
+		MessageKey:    JSONMessageKey,
+		StacktraceKey: JSONStackKey,
+		EncodeTime:    newTimeEncoder(TimeFormatRFC3339, o.utc),
+		EncodeLevel: func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString(levelName(l))
+		},
+		EncodeCaller: zapcore.ShortCallerEncoder,
+		EncodeName:   zapcore.FullNameEncoder,
+	})
+}
+
+// newConsoleSink builds the console core for a single output.
+func newConsoleSink(o options, out io.Writer, color bool) zapcore.Core {
+	// Files and pipes get plain labels with any stray escape sequences stripped.
+	if !color {
+		out = stripANSIWriter{out}
+	}
+	output := zapcore.Lock(zapcore.AddSync(out))
+	return zapcore.NewCore(newConsoleEncoder(o, color), output, zap.LevelEnablerFunc(func(zapcore.Level) bool { return true }))
+}
+
+// newConsoleEncoder builds the console encoder, colored or plain.
+func newConsoleEncoder(o options, color bool) zapcore.Encoder {
+	levelEncoder, nameEncoder := themeEncoders(o.theme, color)
+
+	return errorLinesEncoder{zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
+		TimeKey:          "timestamp",
+		LevelKey:         "level",
+		CallerKey:        "caller", // Shows file:line
+		MessageKey:       "message",
+		StacktraceKey:    "stack", // Printed below the entry, e.g. by RecoverAndLog
+		EncodeTime:       newTimeEncoder(o.timeFormat, o.utc),
+		EncodeLevel:      levelEncoder,               // Add colors to log levels
+		EncodeCaller:     zapcore.ShortCallerEncoder, // Shows file:line
+		NameKey:          "component",
+		EncodeName:       nameEncoder, // Add component field inline
+		ConsoleSeparator: " | ",
+	})}
+}
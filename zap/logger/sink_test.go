@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSinkLevels(t *testing.T) {
+	var all, errs syncBuffer
+	useOutput(t, zapcore.DebugLevel, WithSinks(
+		Sink{Writer: &all, Format: FormatLogfmt},
+		Sink{Writer: &errs, Format: FormatLogfmt, Level: zapcore.ErrorLevel},
+	))
+	PfcpLog.Debug("debug")
+	PfcpLog.Log(NoticeLevel, "notice")
+	PfcpLog.Error("error")
+
+	if n := strings.Count(all.String(), "\n"); n != 3 {
+		t.Errorf("unfiltered sink got %d lines:\n%s", n, all.String())
+	}
+	checkLines(t, errs.String(), `component=PFCP msg=error`)
+}
@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// log/slog Handler
+// -------------------------------------------------------------
+
+// NewSlogHandler returns a slog.Handler that writes through the core of the
+// component logger named component ("" for no component column), so slog
+// output gets the same format, sinks, levels and rate limits:
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler("MAIN")))
+//	slog.With("component", "NRF").Info("registered", "nf_id", id)
+//	slog.Default().WithGroup("PFCP").Info("association setup")
+//
+// Outside any group, two conventions move the entries to another
+// component's column and level. A "component" attribute given to With names
+// the component, creating it if needed. At most maxSlogComponents
+// components are created this way, since third-party code may set any
+// value; past that, an unknown name is kept as a plain attribute on entries
+// without a component logger, levelled like the global logger. A WithGroup
+// named after an existing component, such as "PFCP", selects that
+// component instead of opening a group. Other groups become nested objects,
+// so libraries can still namespace their attributes with groups such as
+// "req".
+//
+// Fields stored in the context by WithContext are added to each record.
+// slog levels map to trace (below debug), debug, info, notice (between info
+// and warn), warn and error.
+func NewSlogHandler(component string) slog.Handler {
+	core, _ := slogCore(component, false)
+	return &slogHandler{name: component, core: core}
+}
+
+// slogHandler is immutable; WithAttrs and WithGroup return copies.
+type slogHandler struct {
+	name   string
+	core   zapcore.Core    // the component's core with fields
+	fields []zapcore.Field // added by WithAttrs, to rebuild core for another component
+	groups []string        // opened by WithGroup, waiting for a field
+	nested bool            // a group has been opened in core
+}
+
+// maxSlogComponents caps the components created for "component"
+// attributes.
+const maxSlogComponents = 32
+
+// slogComponents counts the components created for "component" attributes;
+// levelStateMutex guards it.
+var slogComponents int
+
+// slogCore returns the core of the component logger named name. A capped
+// call fails for a new component once maxSlogComponents components have
+// been created for attributes.
+func slogCore(name string, capped bool) (zapcore.Core, bool) {
+	if capped {
+		levelStateMutex.Lock()
+		_, known := componentLevels[name]
+		if !known && slogComponents == maxSlogComponents {
+			levelStateMutex.Unlock()
+			return nil, false
+		}
+		if !known {
+			slogComponents++
+		}
+		levelStateMutex.Unlock()
+	}
+	return NewComponentLogger(name).Desugar().Core(), true
+}
+
+// knownComponentCore returns the core of the component named name, if a
+// component logger has been created for it.
+func knownComponentCore(name string) (zapcore.Core, bool) {
+	levelStateMutex.Lock()
+	_, known := componentLevels[name]
+	levelStateMutex.Unlock()
+	if !known {
+		return nil, false
+	}
+	return slogCore(name, false)
+}
+
+// slogLevel maps a slog level to a zap or registered level.
+func slogLevel(l slog.Level) zapcore.Level {
+	switch {
+	case l < slog.LevelDebug:
+		return TraceLevel
+	case l < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case l == slog.LevelInfo:
+		return zapcore.InfoLevel
+	case l < slog.LevelWarn:
+		return NoticeLevel
+	case l < slog.LevelError:
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// Enabled implements slog.Handler. It checks the component level only; the
+// rate limit is taken in Handle.
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.core.Enabled(slogLevel(l))
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      slogLevel(r.Level),
+		Time:       r.Time,
+		LoggerName: h.name,
+		Message:    r.Message,
+	}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	if caller && r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Caller = zapcore.EntryCaller{Defined: true, PC: f.PC, File: f.File, Line: f.Line, Function: f.Function}
+	}
+
+	fields := append([]zapcore.Field(nil), ContextFields(ctx)...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogFields(fields, a)
+		return true
+	})
+	if len(fields) > 0 && len(h.groups) > 0 {
+		fields = append(namespaces(h.groups), fields...)
+	}
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	var fields []zapcore.Field
+	for _, a := range attrs {
+		if !h.nested && len(h.groups) == 0 && a.Key == JSONComponentKey && a.Value.Kind() == slog.KindString {
+			core, ok := slogCore(a.Value.String(), true)
+			if ok {
+				h2.name = a.Value.String()
+				h2.core = core.With(h2.fields)
+				continue
+			}
+			core, _ = slogCore("", false)
+			h2.name, h2.core = "", core.With(h2.fields)
+		}
+		fields = appendSlogFields(fields, a)
+	}
+	if len(fields) > 0 {
+		if len(h.groups) > 0 {
+			fields = append(namespaces(h.groups), fields...)
+			h2.groups, h2.nested = nil, true
+		}
+		h2.fields = append(h.fields[:len(h.fields):len(h.fields)], fields...)
+		h2.core = h2.core.With(fields)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler. Outside any group, a group named after
+// an existing component selects that component instead. Like slog's own
+// handlers, a group without any field is left out, so it is opened only
+// when fields arrive.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	if !h.nested && len(h.groups) == 0 {
+		if core, ok := knownComponentCore(name); ok {
+			h2.name, h2.core = name, core.With(h.fields)
+			return &h2
+		}
+	}
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// namespaces opens the groups as nested objects for the fields after them.
+func namespaces(groups []string) []zapcore.Field {
+	fields := make([]zapcore.Field, len(groups))
+	for i, g := range groups {
+		fields[i] = zap.Namespace(g)
+	}
+	return fields
+}
+
+// appendSlogFields appends a as a field. Empty attributes and groups are
+// skipped, and groups without a key are inlined. Durations use
+// time.Duration.String, times RFC 3339 and errors their message, as in the
+// phuslu backend.
+func appendSlogFields(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindGroup:
+		var group []zapcore.Field
+		for _, ga := range v.Group() {
+			group = appendSlogFields(group, ga)
+		}
+		if a.Key == "" {
+			return append(fields, group...)
+		}
+		if len(group) == 0 {
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, fieldObject(group)))
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Stringer(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.String(a.Key, v.Time().Format(time.RFC3339Nano)))
+	}
+	if err, ok := v.Any().(error); ok {
+		return append(fields, zap.String(a.Key, err.Error()))
+	}
+	return append(fields, zap.Any(a.Key, v.Any()))
+}
+
+// fieldObject encodes fields as a nested object.
+type fieldObject []zapcore.Field
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (fo fieldObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range fo {
+		f.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var out *syncBuffer
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		if strings.HasSuffix(t.Name(), "/zero-time") {
+			t.Skip("entries always carry the time they were written")
+		}
+		out = useOutput(t, zapcore.DebugLevel, WithFormat(FormatJSON))
+		return NewSlogHandler("")
+	}, func(t *testing.T) map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(out.String()), &m); err != nil {
+			t.Fatalf("%v: %s", err, out.String())
+		}
+		if msg, ok := m[JSONMessageKey]; ok {
+			delete(m, JSONMessageKey)
+			m[slog.MessageKey] = msg
+		}
+		return m
+	})
+}
+
+func TestSlogHandlerComponent(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel)
+
+	NewComponentLogger("PFCP")
+	l := slog.New(NewSlogHandler("MAIN"))
+	l.Info("started", "port", 8080)
+	l.With("component", "NRF").Info("registered", "nf_id", "smf-1")
+	l.With("component", "NRF").Debug("filtered out")
+	l.WithGroup("req").With("component", "grouped").Warn("kept as field")
+	l.Log(context.Background(), slog.LevelInfo+2, "notice")
+	l.Error("failed", "err", errors.New("boom"), "d", 1500*time.Millisecond)
+	l.WithGroup("PFCP").Info("association setup", "seid", 42)
+	l.WithGroup("req").WithGroup("PFCP").Info("nested", "id", 7)
+
+	// past the cap, unknown components stay attributes
+	levelStateMutex.Lock()
+	created := slogComponents
+	slogComponents = maxSlogComponents
+	levelStateMutex.Unlock()
+	l.With("component", "ue-208930000000003").Info("capped")
+	l.With("component", "NRF").Info("known")
+	levelStateMutex.Lock()
+	slogComponents = created
+	levelStateMutex.Unlock()
+
+	checkLines(t, out.String(),
+		`| INFO  | MAIN  | started | {"port": 8080}`,
+		`| INFO  | NRF   | registered | {"nf_id": "smf-1"}`,
+		`| WARN  | MAIN  | kept as field | {"req": {"component": "grouped"}}`,
+		`| NOTE  | MAIN  | notice`,
+		`| ERR   | MAIN  | failed | {"err": "boom", "d": "1.5s"}`,
+		`| INFO  | PFCP  | association setup | {"seid": 42}`,
+		`| INFO  | MAIN  | nested | {"req": {"PFCP": {"id": 7}}}`,
+		`| INFO  | capped | {"component": "ue-208930000000003"}`,
+		`| INFO  | NRF   | known`,
+	)
+}
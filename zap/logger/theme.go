@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Color Themes
+// -------------------------------------------------------------
+
+// LevelStyle is how one level is rendered on the console.
+type LevelStyle struct {
+	Label string // e.g. "INFO "; labels are padded to the widest one in the theme
+	Color string // ANSI SGR sequence, e.g. "\033[32m" or Color256(196)
+}
+
+// Theme configures console level labels and colors.
+type Theme struct {
+	// Levels is keyed by lower-case level name: "trace", "debug", "info",
+	// "notice", "warn", "error", "dpanic", "panic", "fatal" or any
+	// registered level.
+	Levels map[string]LevelStyle
+
+	// Components colors the component column, keyed by component name
+	// (e.g. "PFCP"). Components without an entry are not colored.
+	Components map[string]string
+}
+
+// Color256 returns the foreground escape for color n of the 256-color palette.
+func Color256(n uint8) string {
+	return "\033[38;5;" + strconv.Itoa(int(n)) + "m"
+}
+
+// Background256 returns the background escape for color n of the 256-color palette.
+func Background256(n uint8) string {
+	return "\033[48;5;" + strconv.Itoa(int(n)) + "m"
+}
+
+// TrueColor returns the 24-bit foreground escape for r, g, b.
+func TrueColor(r, g, b uint8) string {
+	return "\033[38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
+
+// BackgroundTrueColor returns the 24-bit background escape for r, g, b.
+func BackgroundTrueColor(r, g, b uint8) string {
+	return "\033[48;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
+
+// DefaultTheme is the classic 8-color scheme shared with the phuslu bench/logger.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Levels: map[string]LevelStyle{
+			"trace":  {"TRACE", "\033[35m"},         // Magenta
+			"debug":  {"DEBUG", "\033[36m"},         // Cyan
+			"info":   {"INFO ", "\033[32m"},         // Green
+			"notice": {"NOTE ", "\033[34m"},         // Blue
+			"warn":   {"WARN ", "\033[33m"},         // Yellow
+			"error":  {"ERR  ", "\033[31m"},         // Red
+			"dpanic": {"DPANC", "\033[45m\033[37m"}, // White on Magenta
+			"panic":  {"PANIC", "\033[45m\033[37m"}, // White on Magenta
+			"fatal":  {"FATAL", "\033[41m\033[37m"}, // White on Red
+		},
+	}
+}
+
+// HighContrastTheme uses the 256-color palette so errors and PFCP traffic
+// stand out in dense terminals.
+func HighContrastTheme() *Theme {
+	bold := "\033[1m"
+	return &Theme{
+		Levels: map[string]LevelStyle{
+			"trace":  {"TRACE", Color256(244)},
+			"debug":  {"DEBUG", Color256(45)},
+			"info":   {"INFO ", Color256(40)},
+			"notice": {"NOTE ", Color256(39)},
+			"warn":   {"WARN ", bold + Color256(214)},
+			"error":  {"ERR  ", bold + Color256(231) + Background256(160)},
+			"dpanic": {"DPANC", bold + Color256(231) + Background256(90)},
+			"panic":  {"PANIC", bold + Color256(231) + Background256(90)},
+			"fatal":  {"FATAL", bold + Color256(231) + Background256(88)},
+		},
+		Components: map[string]string{
+			"PFCP":   bold + Color256(208),
+			"SESS":   Color256(177),
+			"CHARGE": Color256(220),
+			"SBI":    Color256(117),
+			"NWDAF":  Color256(156),
+		},
+	}
+}
+
+// themeEncoders compiles t (DefaultTheme if nil) into the level and component
+// encoders. Labels are padded to the widest one and, for the levels known at
+// Initialize, rendered once up front.
+func themeEncoders(t *Theme, color bool) (zapcore.LevelEncoder, zapcore.NameEncoder) {
+	if t == nil {
+		t = DefaultTheme()
+	}
+	width := 5
+	for _, s := range t.Levels {
+		if n := utf8.RuneCountInString(s.Label); n > width {
+			width = n
+		}
+	}
+	styles := make(map[string]LevelStyle, len(t.Levels))
+	for name, s := range t.Levels {
+		s.Label = padRight(s.Label, width)
+		styles[strings.ToLower(name)] = s
+	}
+	render := func(level zapcore.Level) string {
+		s, ok := styles[levelName(level)]
+		if !ok {
+			s = LevelStyle{padRight(strings.ToUpper(levelName(level)), width), ""}
+		}
+		if !color || s.Color == "" {
+			return s.Label
+		}
+		return s.Color + s.Label + "\033[0m"
+	}
+
+	// zapcore.Level is an int8: index by level+128.
+	var labels [256]string
+	for l := -128; l < 128; l++ {
+		level := zapcore.Level(l)
+		if level <= zapcore.FatalLevel && levelSeverity(level) != 0 {
+			labels[l+128] = render(level)
+		}
+	}
+	levelEncoder := func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if s := labels[int(level)+128]; s != "" {
+			enc.AppendString(s)
+			return
+		}
+		enc.AppendString(render(level)) // registered after Initialize
+	}
+
+	if !color || len(t.Components) == 0 {
+		return levelEncoder, customComponentEncoder
+	}
+	components := make(map[string]string, len(t.Components))
+	for name, c := range t.Components {
+		components[name] = c + padRight(name, 5) + "\033[0m"
+	}
+	nameEncoder := func(loggerName string, enc zapcore.PrimitiveArrayEncoder) {
+		if s, ok := components[loggerName]; ok {
+			enc.AppendString(s)
+			return
+		}
+		customComponentEncoder(loggerName, enc)
+	}
+	return levelEncoder, nameEncoder
+}
+
+// padRight left-aligns s in a column of width runes, like "%-5s".
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
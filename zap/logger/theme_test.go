@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestThemeEncoders(t *testing.T) {
+	levelEnc, _ := themeEncoders(&Theme{Levels: map[string]LevelStyle{
+		"info":  {"INFO", "\033[32m"},
+		"ERROR": {"ERROR!", TrueColor(255, 0, 0)},
+	}}, true)
+	for level, want := range map[zapcore.Level]string{
+		zapcore.InfoLevel:  "\033[32mINFO  \033[0m", // labels padded to the widest
+		zapcore.ErrorLevel: "\033[38;2;255;0;0mERROR!\033[0m",
+		NoticeLevel:        "NOTICE", // not in the theme
+	} {
+		var out encoderText
+		if levelEnc(level, &out); string(out.b) != want {
+			t.Errorf("%v = %q, want %q", level, out.b, want)
+		}
+	}
+}
+
+func TestThemeComponentColor(t *testing.T) {
+	out := useOutput(t, zapcore.InfoLevel, WithTheme(HighContrastTheme()), WithColor(ColorAlways))
+	PfcpLog.Info("association setup")
+	if got := out.String(); !strings.Contains(got, "\033[38;5;40mINFO \033[0m | \033[1m\033[38;5;208mPFCP \033[0m | ") {
+		t.Errorf("high contrast line = %q", got)
+	}
+
+	out = useOutput(t, zapcore.InfoLevel, WithTheme(HighContrastTheme()))
+	PfcpLog.Info("association setup")
+	if got := out.String(); strings.Contains(got, "\033") {
+		t.Errorf("plain line has escapes: %q", got)
+	}
+}
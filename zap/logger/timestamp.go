@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------------------------------------------
+// Timestamp Layouts (cached per second)
+// -------------------------------------------------------------
+
+// Timestamp formats accepted by WithTimeFormat. Any other value is used as a
+// time.Format layout.
+const (
+	// TimeFormatDefault keeps the historical zap layout (TimeFormatZap).
+	TimeFormatDefault = ""
+	// TimeFormatZap is "2006-01-02 | 15:04:05.000".
+	TimeFormatZap = "2006-01-02 | 15:04:05.000"
+	// TimeFormatRFC3339 is RFC3339 with milliseconds, as phuslu/log prints it.
+	TimeFormatRFC3339 = "2006-01-02T15:04:05.000Z07:00"
+	// TimeFormatEpochMillis prints Unix time in milliseconds.
+	TimeFormatEpochMillis = "epoch_ms"
+	// TimeFormatEpochNanos prints Unix time in nanoseconds.
+	TimeFormatEpochNanos = "epoch_ns"
+)
+
+// cachedTimeEncoder formats timestamps with a layout. Everything except the
+// fractional seconds only changes once per second, so the text before and
+// after the fraction is cached and reused until the second rolls over.
+type cachedTimeEncoder struct {
+	loc        *time.Location
+	head, tail string // layout split around the fractional seconds
+	frac       int    // digits of fractional seconds, 0 if none
+
+	cache atomic.Pointer[timeCache]
+}
+
+// timeCache holds the rendering of one wall-clock second.
+type timeCache struct {
+	unix int64
+	head []byte
+	tail []byte
+}
+
+// timeBuffer is a pooled scratch buffer for one formatted timestamp.
+type timeBuffer struct {
+	b []byte
+}
+
+var timeBufferPool = sync.Pool{
+	New: func() any {
+		return &timeBuffer{b: make([]byte, 0, 64)}
+	},
+}
+
+// newTimeEncoder returns a zapcore.TimeEncoder for one of the TimeFormat*
+// constants or a time.Format layout.
+func newTimeEncoder(format string, utc bool) zapcore.TimeEncoder {
+	loc := time.Local
+	if utc {
+		loc = time.UTC
+	}
+	switch format {
+	case TimeFormatDefault:
+		format = TimeFormatZap
+	case TimeFormatEpochMillis:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendInt64(t.UnixMilli())
+		}
+	case TimeFormatEpochNanos:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendInt64(t.UnixNano())
+		}
+	}
+
+	head, tail, frac, slow := splitFraction(format)
+	if slow {
+		// ".999" style fractions trim zeros, so format the whole layout per line
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			zapcore.TimeEncoderOfLayout(format)(t.In(loc), enc)
+		}
+	}
+	e := &cachedTimeEncoder{loc: loc, head: head, tail: tail, frac: frac}
+	return e.encode
+}
+
+// splitFraction splits layout around its first fractional-second element,
+// e.g. "15:04:05.000 MST" -> ("15:04:05.", " MST", 3).
+func splitFraction(layout string) (head, tail string, digits int, slow bool) {
+	for i := 0; i+1 < len(layout); i++ {
+		if (layout[i] != '.' && layout[i] != ',') || (layout[i+1] != '0' && layout[i+1] != '9') {
+			continue
+		}
+		// the fraction must follow the seconds element
+		if i < 2 || layout[i-2:i] != "05" {
+			continue
+		}
+		if layout[i+1] == '9' {
+			return layout, "", 0, true
+		}
+		j := i + 1
+		for j < len(layout) && layout[j] == '0' {
+			j++
+		}
+		return layout[:i+1], layout[j:], j - i - 1, false
+	}
+	return layout, "", 0, false
+}
+
+func (e *cachedTimeEncoder) encode(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	unix := t.Unix()
+	c := e.cache.Load()
+	if c == nil || c.unix != unix {
+		st := time.Unix(unix, 0).In(e.loc)
+		c = &timeCache{
+			unix: unix,
+			head: st.AppendFormat(nil, e.head),
+			tail: st.AppendFormat(nil, e.tail),
+		}
+		e.cache.Store(c)
+	}
+
+	tb := timeBufferPool.Get().(*timeBuffer)
+	b := append(tb.b[:0], c.head...)
+	if e.frac > 0 {
+		// nine zero-padded digits of nanoseconds, truncated to the layout's precision
+		var digits [9]byte
+		ns := t.Nanosecond()
+		for i := 8; i >= 0; i-- {
+			digits[i] = byte('0' + ns%10)
+			ns /= 10
+		}
+		b = append(b, digits[:e.frac]...)
+	}
+	b = append(b, c.tail...)
+	enc.AppendByteString(b)
+	tb.b = b
+	timeBufferPool.Put(tb)
+}
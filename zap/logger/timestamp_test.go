@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// encoderText collects what a time or level encoder appends. The encoders
+// only call the methods below.
+type encoderText struct {
+	zapcore.PrimitiveArrayEncoder
+	b []byte
+}
+
+func (e *encoderText) AppendByteString(b []byte) { e.b = append(e.b, b...) }
+func (e *encoderText) AppendString(s string)     { e.b = append(e.b, s...) }
+func (e *encoderText) AppendInt64(i int64)       { e.b = strconv.AppendInt(e.b, i, 10) }
+
+func TestTimeFormat(t *testing.T) {
+	ts := time.Date(2025, 3, 8, 12, 34, 56, 789e6, time.FixedZone("", 5*3600+1800))
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{TimeFormatZap, "2025-03-08 | 07:04:56.789"},
+		{TimeFormatRFC3339, "2025-03-08T07:04:56.789Z"},
+		{TimeFormatEpochMillis, "1741417496789"},
+		{TimeFormatEpochNanos, "1741417496789000000"},
+		{"15:04:05.000000 MST", "07:04:56.789000 UTC"},
+		{"15:04:05,0", "07:04:56,7"},
+		{"Jan _2 15:04:05", "Mar  8 07:04:56"},
+		{time.StampMilli, "Mar  8 07:04:56.789"},
+		{"15:04:05.999", "07:04:56.789"},
+	} {
+		enc := newTimeEncoder(tc.format, true)
+		// twice: once rendering the second, once from the cache
+		for i := 0; i < 2; i++ {
+			var out encoderText
+			if enc(ts, &out); string(out.b) != tc.want {
+				t.Errorf("%q pass %d: got %q, want %q", tc.format, i, out.b, tc.want)
+			}
+		}
+	}
+}
+
+func TestTimeFormatDefault(t *testing.T) {
+	var out encoderText
+	newTimeEncoder(TimeFormatDefault, true)(time.Date(2025, 3, 8, 12, 34, 56, 789e6, time.FixedZone("", -3600)), &out)
+	if got := string(out.b); got != "2025-03-08 | 13:34:56.789" {
+		t.Errorf("UTC default = %q", got)
+	}
+}
+
+func TestTimeFormatCachedZeroAlloc(t *testing.T) {
+	enc := newTimeEncoder(TimeFormatZap, false)
+	out := &encoderText{b: make([]byte, 0, 64)}
+	ts := time.Date(2025, 3, 8, 12, 34, 56, 789e6, time.UTC)
+	enc(ts, out)
+	allocs := testing.AllocsPerRun(1000, func() {
+		out.b = out.b[:0]
+		enc(ts.Add(time.Millisecond), out)
+	})
+	if allocs != 0 {
+		t.Errorf("cached time encoder allocates %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkTimeFormatZap(b *testing.B) {
+	enc := newTimeEncoder(TimeFormatZap, false)
+	out := &encoderText{b: make([]byte, 0, 64)}
+	ts := time.Date(2025, 3, 8, 12, 34, 56, 789e6, time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		out.b = out.b[:0]
+		enc(ts, out)
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
-	"go.uber.org/zap/zapcore"
 	"bench/logger"
 )
 
@@ -18,7 +20,7 @@ func main() {
 	var results []result
 	initLogger()
 
-	s ,e := benchmarknormal()
+	s, e := benchmarknormal()
 	results = append(results, result{
 		name:     "Benchmark",
 		duration: e.Sub(s),
@@ -28,6 +30,50 @@ func main() {
 		name:     "Benchmarkformatted",
 		duration: e.Sub(s),
 	})
+	s, e = benchmarkcaller()
+	results = append(results, result{
+		name:     "Caller",
+		duration: e.Sub(s),
+	})
+
+	// Encoder cost per output format, discarding the output so only the
+	// encoding is measured
+	for _, format := range []string{logger.FormatConsole, logger.FormatJSON, logger.FormatLogfmt} {
+		if err := logger.Reconfigure(logger.GetLevel(), logger.WithFormat(format), logger.WithWriter(io.Discard)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		s, e = benchmarknormal()
+		results = append(results, result{
+			name:     "Format " + format,
+			duration: e.Sub(s),
+		})
+	}
+
+	// Sampling the hot-path message: the first 100 per second, then 1 in 100
+	sampling := logger.Sampling{Tick: time.Second, First: 100, Thereafter: 100}
+	if err := logger.Reconfigure(logger.GetLevel(), logger.WithSampling(sampling), logger.WithWriter(io.Discard)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	s, e = benchmarknormal()
+	results = append(results, result{
+		name:     "Sampled",
+		duration: e.Sub(s),
+	})
+
+	// Redacting subscriber identities; entries without any, like these, only
+	// pay for the scan
+	redaction := logger.Redaction{Keep: 5}
+	if err := logger.Reconfigure(logger.GetLevel(), logger.WithRedaction(redaction), logger.WithWriter(io.Discard)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	s, e = benchmarknormal()
+	results = append(results, result{
+		name:     "Redacted",
+		duration: e.Sub(s),
+	})
 	// s, e := consoleWriter()
 	// results = append(results, result{
 	// 	name:     "ConsoleWriter",
@@ -78,7 +124,20 @@ func main() {
 }
 
 func initLogger() {
-	logger.Initialize(zapcore.InfoLevel)
+	// From the config file, if one is given, and the LOG_* environment variables
+	configPath := flag.String("config", "", "logger configuration file (YAML or JSON)")
+	flag.Parse()
+	c, err := logger.ConfigFromEnv()
+	if *configPath != "" {
+		c, err = logger.LoadConfig(*configPath)
+	}
+	if err == nil {
+		err = logger.ApplyConfig(c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
 
 func benchmarkformatted() (time.Time, time.Time) {
@@ -90,6 +149,18 @@ func benchmarkformatted() (time.Time, time.Time) {
 	return start, end
 }
 
+// benchmarkcaller repeats benchmarknormal with the caller (file:line) resolved
+// for every entry, as logger.WithCaller does
+func benchmarkcaller() (time.Time, time.Time) {
+	callerLog := logger.CallerLogger(logger.MainLog, 0)
+	start := time.Now()
+	for i := 0; i < 500000; i++ {
+		callerLog.Info("Packet processed successfully", "UE_ID", 1001, "iteration", i)
+	}
+	end := time.Now()
+	return start, end
+}
+
 func benchmarknormal() (time.Time, time.Time) {
 	start := time.Now()
 	for i := 0; i < 500000; i++ {
@@ -98,6 +169,7 @@ func benchmarknormal() (time.Time, time.Time) {
 	end := time.Now()
 	return start, end
 }
+
 // 1) ConsoleWriter
 // func consoleWriter() (time.Time, time.Time) {
 // 	logger := log.Logger{